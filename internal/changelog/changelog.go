@@ -0,0 +1,111 @@
+// Package changelog provides release note generation for the bumpit tool.
+// It groups the commits of a release by the type of version bump they trigger.
+package changelog
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/crazywolf132/bumpit/internal/config"
+)
+
+// Entry represents a single commit in the changelog
+type Entry struct {
+	Type       string
+	Subject    string
+	SubEntries []string
+}
+
+// sections lists the changelog headings in the order they are rendered
+var sections = []struct {
+	commitType string
+	title      string
+}{
+	{"major", "Breaking Changes"},
+	{"minor", "Features"},
+	{"patch", "Fixes"},
+	{"none", "Other Changes"},
+}
+
+// sectionType returns the section an entry of the given type is listed under.
+// Additional version components are listed with the other changes.
+func sectionType(commitType string) string {
+	for _, section := range sections {
+		if section.commitType == commitType {
+			return commitType
+		}
+	}
+	return "none"
+}
+
+// bullet matches a markdown list item such as "* fix typo" or "- fix typo"
+var bullet = regexp.MustCompile(`^\s*[*-]\s+(.+)$`)
+
+// Entries builds changelog entries from commit messages, leaving out the commits
+// ignored by the configuration. With squash.body_entries the bullet points of each commit body become
+// sub-entries of that commit. They never influence the entry type.
+func Entries(cfg *config.Config, commits []string) []Entry {
+	entries := make([]Entry, 0, len(commits))
+	for _, commit := range commits {
+		if cfg.IsIgnored(commit, "", nil) {
+			continue
+		}
+		lines := strings.Split(strings.TrimSpace(commit), "\n")
+		entry := Entry{
+			Type:    cfg.GetCommitType(commit),
+			Subject: strings.TrimSpace(lines[0]),
+		}
+
+		if cfg.Squash.BodyEntries {
+			for _, line := range lines[1:] {
+				if m := bullet.FindStringSubmatch(line); m != nil {
+					entry.SubEntries = append(entry.SubEntries, strings.TrimSpace(m[1]))
+				}
+			}
+		}
+
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+// Render formats the entries as markdown release notes for the given version
+func Render(version string, entries []Entry) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "## %s\n", version)
+
+	for _, section := range sections {
+		var matched []Entry
+		for _, entry := range entries {
+			if sectionType(entry.Type) == section.commitType {
+				matched = append(matched, entry)
+			}
+		}
+		if len(matched) == 0 {
+			continue
+		}
+
+		fmt.Fprintf(&b, "\n### %s\n\n", section.title)
+		for _, entry := range matched {
+			fmt.Fprintf(&b, "- %s\n", entry.Subject)
+			for _, sub := range entry.SubEntries {
+				fmt.Fprintf(&b, "  - %s\n", sub)
+			}
+		}
+	}
+
+	return b.String()
+}
+
+// TagMessage renders the tag message template of a release. {tag}, {version} and {previous}
+// are replaced by the release tag, its version and the previous release tag,
+// {changelog} by the release notes rendered from the entries.
+func TagMessage(template, tag, version, previous string, entries []Entry) string {
+	return strings.NewReplacer(
+		"{tag}", tag,
+		"{version}", version,
+		"{previous}", previous,
+		"{changelog}", Render(tag, entries),
+	).Replace(template)
+}
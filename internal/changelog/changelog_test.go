@@ -0,0 +1,87 @@
+package changelog
+
+import (
+	"testing"
+
+	"github.com/crazywolf132/bumpit/internal/config"
+)
+
+func newTestConfig() *config.Config {
+	return &config.Config{
+		CommitTypes: config.CommitTypes{
+			Major: []string{"BREAKING CHANGE"},
+			Minor: []string{"feat"},
+			Patch: []string{"fix"},
+		},
+		Squash: config.SquashConfig{
+			SubjectOnly: true,
+			BodyEntries: true,
+		},
+	}
+}
+
+func TestEntries(t *testing.T) {
+	commits := []string{
+		"feat: add exporter (#42)\n\n* fix typo in exporter\n* fix lint\n- add tests",
+		"chore: update deps (#43)\n\n* fix go.sum",
+	}
+
+	entries := Entries(newTestConfig(), commits)
+	if len(entries) != 2 {
+		t.Fatalf("Entries() = %v entries, want 2", len(entries))
+	}
+
+	if entries[0].Type != "minor" || entries[0].Subject != "feat: add exporter (#42)" {
+		t.Errorf("Entries() first entry = %+v", entries[0])
+	}
+	if len(entries[0].SubEntries) != 3 || entries[0].SubEntries[0] != "fix typo in exporter" {
+		t.Errorf("Entries() sub entries = %v", entries[0].SubEntries)
+	}
+
+	// Bullets in the body must not turn the squashed commit into a fix
+	if entries[1].Type != "none" {
+		t.Errorf("Entries() second entry type = %v, want none", entries[1].Type)
+	}
+}
+
+func TestEntriesIgnored(t *testing.T) {
+	cfg := newTestConfig()
+	cfg.Ignore = config.IgnoreConfig{
+		Types:       []string{"chore(release)"},
+		Scopes:      []string{"deps"},
+		SkipMarkers: []string{"[skip release]"},
+	}
+	commits := []string{
+		"chore(release): v1.1.0",
+		"fix(deps): bump cobra",
+		"feat: experimental\n\n[skip release]",
+		"fix: real bug",
+	}
+
+	entries := Entries(cfg, commits)
+	if len(entries) != 1 || entries[0].Subject != "fix: real bug" {
+		t.Errorf("Entries() = %+v, want only the fix that is not ignored", entries)
+	}
+}
+
+func TestRender(t *testing.T) {
+	entries := []Entry{
+		{Type: "patch", Subject: "fix: bug"},
+		{Type: "minor", Subject: "feat: thing", SubEntries: []string{"add tests"}},
+		{Type: "build", Subject: "build: installer"},
+	}
+
+	want := "## v1.2.0\n\n### Features\n\n- feat: thing\n  - add tests\n\n### Fixes\n\n- fix: bug\n\n### Other Changes\n\n- build: installer\n"
+	if got := Render("v1.2.0", entries); got != want {
+		t.Errorf("Render() = %q, want %q", got, want)
+	}
+}
+
+func TestTagMessage(t *testing.T) {
+	entries := []Entry{{Type: "patch", Subject: "fix: bug"}}
+
+	want := "Release v1.2.1 (1.2.1) after v1.2.0\n\n## v1.2.1\n\n### Fixes\n\n- fix: bug\n"
+	if got := TagMessage("Release {tag} ({version}) after {previous}\n\n{changelog}", "v1.2.1", "1.2.1", "v1.2.0", entries); got != want {
+		t.Errorf("TagMessage() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,120 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// CommandConfig is a command run by bumpit, such as default_command or a release hook.
+// It is written as a string, which sets Run, as a list of arguments, which sets Args,
+// or as a map of the fields below.
+type CommandConfig struct {
+	// Run is the command line. It is split into arguments and run without a shell unless Shell is set.
+	Run string `yaml:"run"`
+	// Args are the program and its arguments, run directly without a shell
+	Args []string `yaml:"args"`
+	// Shell runs Run with the platform shell, sh or cmd on Windows
+	Shell bool `yaml:"shell"`
+	// Dir is the working directory of the command, relative to the current directory
+	Dir string `yaml:"dir"`
+	// Timeout stops the command after the given duration, zero means no limit
+	Timeout time.Duration `yaml:"timeout"`
+}
+
+// IsZero reports whether no command is set
+func (c CommandConfig) IsZero() bool {
+	return c.Run == "" && len(c.Args) == 0
+}
+
+// validateCommand checks that exactly one of run and args is set and that
+// the shell is only requested for a command line
+func validateCommand(c CommandConfig) error {
+	switch {
+	case c.Run == "" && len(c.Args) == 0:
+		return fmt.Errorf("run or args must be set")
+	case c.Run != "" && len(c.Args) > 0:
+		return fmt.Errorf("run and args cannot both be set")
+	case len(c.Args) > 0 && c.Args[0] == "":
+		return fmt.Errorf("args must start with the program to run")
+	case c.Shell && len(c.Args) > 0:
+		return fmt.Errorf("shell can only be used with run")
+	case c.Timeout < 0:
+		return fmt.Errorf("timeout must not be negative")
+	}
+	if op := shellOperator(c.Run); op != "" && !c.Shell {
+		return fmt.Errorf("%q needs a shell but run is not passed to one: set shell: true, or use args and dir", op)
+	}
+	return nil
+}
+
+// shellOperator returns the first shell operator outside quotes in a command line, such as
+// "&&" or a line continuation, or "" when the line is a plain command
+func shellOperator(line string) string {
+	var quote byte
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		switch {
+		case quote == '\'':
+			if c == '\'' {
+				quote = 0
+			}
+		case quote == '"':
+			switch {
+			case c == '"':
+				quote = 0
+			case c == '\\':
+				i++
+			case c == '`':
+				return "`"
+			case strings.HasPrefix(line[i:], "$("):
+				return "$("
+			}
+		case c == '\'' || c == '"':
+			quote = c
+		case strings.HasPrefix(line[i:], "\\\n"):
+			return "\\"
+		case c == '\\':
+			i++
+		case strings.HasPrefix(line[i:], "$("):
+			return "$("
+		case strings.ContainsRune("&|;<>`", rune(c)):
+			if i+1 < len(line) && (c == '&' || c == '|') && line[i+1] == c {
+				return line[i : i+2]
+			}
+			return string(c)
+		}
+	}
+	return ""
+}
+
+// stringToCommandHookFunc decodes a command written as a string or a list of arguments into a CommandConfig.
+// A string given for a list of commands, such as a hook stage, is decoded as a single command line.
+func stringToCommandHookFunc() func(reflect.Type, reflect.Type, interface{}) (interface{}, error) {
+	return func(from, to reflect.Type, data interface{}) (interface{}, error) {
+		if to == reflect.TypeOf([]CommandConfig{}) && from.Kind() == reflect.String {
+			return []CommandConfig{{Run: data.(string)}}, nil
+		}
+		if to != reflect.TypeOf(CommandConfig{}) {
+			return data, nil
+		}
+		switch from.Kind() {
+		case reflect.String:
+			return CommandConfig{Run: data.(string)}, nil
+		case reflect.Slice:
+			switch items := data.(type) {
+			case []string:
+				return CommandConfig{Args: items}, nil
+			case []interface{}:
+				args := make([]string, len(items))
+				for i, arg := range items {
+					args[i] = fmt.Sprint(arg)
+				}
+				return CommandConfig{Args: args}, nil
+			}
+			return nil, fmt.Errorf("command must be a list of arguments, got %T", data)
+		}
+		return data, nil
+	}
+}
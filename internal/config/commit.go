@@ -0,0 +1,202 @@
+package config
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// conventionalSubject matches the "type(scope)!: description" header of a conventional commit
+var conventionalSubject = regexp.MustCompile(`^(\w[\w-]*)(?:\(([^)]*)\))?(!)?:`)
+
+// ParseConventionalCommit extracts the type and scope from the subject line of a
+// conventional commit and reports whether the header marks a breaking change.
+// It returns empty strings when the subject does not follow the convention.
+func ParseConventionalCommit(commitMsg string) (string, string, bool) {
+	subject := strings.TrimSpace(strings.SplitN(commitMsg, "\n", 2)[0])
+	m := conventionalSubject.FindStringSubmatch(subject)
+	if m == nil {
+		return "", "", false
+	}
+	return strings.ToLower(m[1]), strings.ToLower(strings.TrimSpace(m[2])), m[3] == "!"
+}
+
+var (
+	patternCache   = make(map[string]*regexp.Regexp)
+	patternCacheMu sync.Mutex
+)
+
+// compilePattern compiles a regular expression, caching the result
+func compilePattern(pattern string) (*regexp.Regexp, error) {
+	patternCacheMu.Lock()
+	defer patternCacheMu.Unlock()
+
+	if re, ok := patternCache[pattern]; ok {
+		return re, nil
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+	patternCache[pattern] = re
+	return re, nil
+}
+
+// regexRule returns the expression of a commit type entry wrapped in slashes
+func regexRule(rule string) (string, bool) {
+	if len(rule) > 2 && strings.HasPrefix(rule, "/") && strings.HasSuffix(rule, "/") {
+		return rule[1 : len(rule)-1], true
+	}
+	return "", false
+}
+
+// matchesRule checks a commit message against a commit type entry, which is either
+// a case-sensitive substring or a regular expression wrapped in slashes
+func matchesRule(msg, rule string) bool {
+	pattern, ok := regexRule(rule)
+	if !ok {
+		return strings.Contains(msg, rule)
+	}
+	re, err := compilePattern(pattern)
+	return err == nil && re.MatchString(msg)
+}
+
+// Matches reports whether a commit message satisfies every condition of the rule
+func (r CommitRule) Matches(commitMsg string) bool {
+	if r.Type != "" || r.Scope != "" || r.Breaking {
+		commitType, scope, breaking := ParseConventionalCommit(commitMsg)
+		if r.Type != "" && !strings.EqualFold(r.Type, commitType) {
+			return false
+		}
+		if r.Scope != "" {
+			if matched, _ := filepath.Match(strings.ToLower(r.Scope), scope); !matched {
+				return false
+			}
+		}
+		if r.Breaking && !breaking && !strings.Contains(commitMsg, "BREAKING CHANGE:") {
+			return false
+		}
+	}
+	if r.Pattern != "" {
+		re, err := compilePattern(r.Pattern)
+		if err != nil || !re.MatchString(commitMsg) {
+			return false
+		}
+	}
+	return true
+}
+
+// String describes the rule in conventional commit notation, e.g. "feat(api)!" or "/pattern/"
+func (r CommitRule) String() string {
+	var b strings.Builder
+	b.WriteString(r.Type)
+	if r.Type == "" && (r.Scope != "" || r.Breaking) {
+		b.WriteString("*")
+	}
+	if r.Scope != "" {
+		b.WriteString("(" + r.Scope + ")")
+	}
+	if r.Breaking {
+		b.WriteString("!")
+	}
+	if r.Pattern != "" {
+		if b.Len() > 0 {
+			b.WriteString(" ")
+		}
+		b.WriteString("/" + r.Pattern + "/")
+	}
+	return b.String()
+}
+
+// validateCommitTypes checks the regular expressions and rules of a raw commit_types value.
+// Rules may bump any of the given levels or "none".
+func validateCommitTypes(raw interface{}, levels []string) error {
+	var types CommitTypes
+	if err := decodeRaw(raw, &types); err != nil {
+		return fmt.Errorf("invalid commit types: %v", err)
+	}
+
+	for _, list := range [][]string{types.Major, types.Minor, types.Patch} {
+		for _, entry := range list {
+			if pattern, ok := regexRule(entry); ok {
+				if _, err := regexp.Compile(pattern); err != nil {
+					return fmt.Errorf("invalid commit type pattern %s: %v", entry, err)
+				}
+			}
+		}
+	}
+
+	for i, rule := range types.Rules {
+		if rule.Bump != "none" && !contains(levels, rule.Bump) {
+			return fmt.Errorf("invalid bump %q for commit rule %d: must be one of %s or none", rule.Bump, i+1, strings.Join(levels, ", "))
+		}
+		if rule.Type == "" && rule.Scope == "" && !rule.Breaking && rule.Pattern == "" {
+			return fmt.Errorf("commit rule %d must set at least one of type, scope, breaking or pattern", i+1)
+		}
+		if rule.Pattern != "" {
+			if _, err := regexp.Compile(rule.Pattern); err != nil {
+				return fmt.Errorf("invalid pattern for commit rule %d: %v", i+1, err)
+			}
+		}
+	}
+	return nil
+}
+
+// IsIgnored reports whether a commit should be left out of the version calculation.
+// author is matched in the "Name <email>" form and files are the paths changed by the commit;
+// either may be empty when the information is not available.
+func (c *Config) IsIgnored(commitMsg, author string, files []string) bool {
+	for _, marker := range c.Ignore.SkipMarkers {
+		if marker != "" && hasPrefix(commitMsg, marker) {
+			return true
+		}
+	}
+
+	commitType, scope, _ := ParseConventionalCommit(commitMsg)
+	if commitType != "" {
+		for _, t := range c.Ignore.Types {
+			t = strings.ToLower(t)
+			if t == commitType || t == commitType+"("+scope+")" {
+				return true
+			}
+		}
+	}
+	if scope != "" {
+		for _, s := range c.Ignore.Scopes {
+			if strings.ToLower(s) == scope {
+				return true
+			}
+		}
+	}
+
+	if author != "" {
+		for _, a := range c.Ignore.Authors {
+			if a != "" && hasPrefix(author, a) {
+				return true
+			}
+		}
+	}
+
+	return len(c.Ignore.Paths) > 0 && len(files) > 0 && allFilesIgnored(c.Ignore.Paths, files)
+}
+
+// allFilesIgnored reports whether every file is covered by one of the ignore patterns.
+// A pattern matches a file either as a glob or as a parent directory.
+func allFilesIgnored(patterns, files []string) bool {
+	for _, file := range files {
+		ignored := false
+		for _, pattern := range patterns {
+			pattern = strings.TrimSuffix(pattern, "/")
+			if matched, _ := filepath.Match(pattern, file); matched || strings.HasPrefix(file, pattern+"/") {
+				ignored = true
+				break
+			}
+		}
+		if !ignored {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,151 @@
+package config
+
+import "testing"
+
+func TestParseConventionalCommit(t *testing.T) {
+	tests := []struct {
+		msg          string
+		wantType     string
+		wantScope    string
+		wantBreaking bool
+	}{
+		{"feat: add thing", "feat", "", false},
+		{"fix(API): handle nil\n\nbody", "fix", "api", false},
+		{"refactor(core)!: drop v1", "refactor", "core", true},
+		{"Update README", "", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.msg, func(t *testing.T) {
+			gotType, gotScope, gotBreaking := ParseConventionalCommit(tt.msg)
+			if gotType != tt.wantType || gotScope != tt.wantScope || gotBreaking != tt.wantBreaking {
+				t.Errorf("ParseConventionalCommit() = (%v, %v, %v), want (%v, %v, %v)",
+					gotType, gotScope, gotBreaking, tt.wantType, tt.wantScope, tt.wantBreaking)
+			}
+		})
+	}
+}
+
+func TestIsIgnored(t *testing.T) {
+	cfg := &Config{
+		Ignore: IgnoreConfig{
+			Types:       []string{"chore(release)", "ci"},
+			Scopes:      []string{"deps"},
+			Authors:     []string{"dependabot[bot]"},
+			Paths:       []string{"docs", "*.md"},
+			SkipMarkers: []string{"[skip release]"},
+		},
+	}
+
+	tests := []struct {
+		name   string
+		msg    string
+		author string
+		files  []string
+		want   bool
+	}{
+		{"type with scope", "chore(release): v1.2.0", "", nil, true},
+		{"same type other scope", "chore(build): tweak", "", nil, false},
+		{"type", "ci: cache modules", "", nil, true},
+		{"scope", "fix(deps): bump semver", "", nil, true},
+		{"author", "fix: bump semver", "dependabot[bot] <support@github.com>", nil, true},
+		{"skip marker", "feat: wip\n\n[skip release]", "", nil, true},
+		{"all files ignored", "fix: typo", "", []string{"docs/intro.md", "README.md"}, true},
+		{"some files relevant", "fix: typo", "", []string{"docs/intro.md", "main.go"}, false},
+		{"regular commit", "feat: new feature", "Jane <jane@example.com>", []string{"main.go"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := cfg.IsIgnored(tt.msg, tt.author, tt.files); got != tt.want {
+				t.Errorf("IsIgnored() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMatchCommitTypeRules(t *testing.T) {
+	cfg := &Config{
+		CommitTypes: CommitTypes{
+			Major: []string{"BREAKING CHANGE"},
+			Minor: []string{"feat"},
+			Patch: []string{"fix", `/^perf(\(\w+\))?:/`},
+			Rules: []CommitRule{
+				{Type: "feat", Scope: "docs", Bump: "patch"},
+				{Breaking: true, Bump: "major"},
+				{Type: "chore", Pattern: `(?i)security`, Bump: "patch"},
+				{Type: "docs", Bump: "none"},
+			},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		msg      string
+		wantType string
+		wantRule string
+	}{
+		{"scoped rule refines prefix list", "feat(docs): new guide", "patch", "feat(docs)"},
+		{"other scope falls through to list", "feat(api): new endpoint", "minor", "feat"},
+		{"breaking header", "fix(api)!: drop field", "major", "*!"},
+		{"type and pattern", "chore: Security patch for deps", "patch", "chore /(?i)security/"},
+		{"type without pattern match", "chore: tidy", "none", ""},
+		{"explicit no bump", "docs: fix typo", "none", "docs"},
+		{"regex entry", "perf(db): faster queries", "patch", `/^perf(\(\w+\))?:/`},
+		{"regex entry anchored", "refactor: perf: nope", "none", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotType, gotRule := cfg.MatchCommitType(tt.msg)
+			if gotType != tt.wantType || gotRule != tt.wantRule {
+				t.Errorf("MatchCommitType() = (%v, %v), want (%v, %v)", gotType, gotRule, tt.wantType, tt.wantRule)
+			}
+		})
+	}
+}
+
+func TestValidateCommitTypes(t *testing.T) {
+	tests := []struct {
+		name    string
+		raw     interface{}
+		wantErr bool
+	}{
+		{
+			name: "valid rules",
+			raw: map[string]interface{}{
+				"minor": []interface{}{"feat", "/^feature:/"},
+				"rules": []interface{}{
+					map[string]interface{}{"type": "feat", "scope": "docs", "bump": "patch"},
+				},
+			},
+		},
+		{
+			name:    "invalid regex entry",
+			raw:     map[string]interface{}{"minor": []interface{}{"/feat(/"}},
+			wantErr: true,
+		},
+		{
+			name: "invalid bump",
+			raw: map[string]interface{}{
+				"rules": []interface{}{map[string]interface{}{"type": "feat", "bump": "huge"}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "rule without conditions",
+			raw: map[string]interface{}{
+				"rules": []interface{}{map[string]interface{}{"bump": "minor"}},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := validateCommitTypes(tt.raw, []string{"major", "minor", "patch"}); (err != nil) != tt.wantErr {
+				t.Errorf("validateCommitTypes() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
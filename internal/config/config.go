@@ -4,36 +4,154 @@ package config
 
 import (
 	"fmt"
+	"github.com/mitchellh/mapstructure"
 	"github.com/spf13/viper"
 	"os"
+	"path"
 	"path/filepath"
+	"regexp"
 	"strings"
+	"time"
 )
 
 // Config represents the main configuration structure for bumpit.
 type Config struct {
-	VersionPrefix  string       `yaml:"version_prefix"`
-	VersionFormat  string       `yaml:"version_format"`
-	PreRelease     string       `yaml:"pre_release"`
-	BuildMetadata  string       `yaml:"build_metadata"`
-	DefaultCommand string       `yaml:"default_command"`
-	CommitTypes    CommitTypes  `yaml:"commit_types"`
-	Git            GitConfig    `yaml:"git"`
-	Output         OutputConfig `yaml:"output"`
-	Paths          []PathConfig `yaml:"paths"`
+	VersionPrefix  string            `yaml:"version_prefix"`
+	VersionFormat  string            `yaml:"version_format"`
+	PreRelease     string            `yaml:"pre_release"`
+	BuildMetadata  string            `yaml:"build_metadata"`
+	Dialect        string            `yaml:"dialect"`
+	DefaultCommand CommandConfig     `yaml:"default_command"`
+	ZeroMajor      string            `yaml:"zero_major"`
+	UnmatchedBump  string            `yaml:"unmatched_bump"`
+	CommitTypes    CommitTypes       `yaml:"commit_types"`
+	Ignore         IgnoreConfig      `yaml:"ignore"`
+	Squash         SquashConfig      `yaml:"squash"`
+	Components     []ComponentConfig `yaml:"components"`
+	Git            GitConfig         `yaml:"git"`
+	Output         OutputConfig      `yaml:"output"`
+	Preflight      PreflightConfig   `yaml:"preflight"`
+	Hooks          HooksConfig       `yaml:"hooks"`
+	Paths          []PathConfig      `yaml:"paths"`
+
+	// sources are the configuration files read by LoadConfig, in order of precedence
+	sources []source
 }
 
 // CommitTypes defines which commit message prefixes trigger different types of version bumps.
+// Entries wrapped in slashes, such as "/^feat\\(api\\)/", are matched as regular expressions.
+// Rules are checked before the plain lists, so they can refine them.
 type CommitTypes struct {
-	Major []string `yaml:"major"`
-	Minor []string `yaml:"minor"`
-	Patch []string `yaml:"patch"`
+	Major []string     `yaml:"major"`
+	Minor []string     `yaml:"minor"`
+	Patch []string     `yaml:"patch"`
+	Rules []CommitRule `yaml:"rules"`
+}
+
+// CommitRule is a structured matcher that maps matching commits to a bump.
+// Every field that is set must match for the rule to apply.
+type CommitRule struct {
+	Type     string `yaml:"type"`
+	Scope    string `yaml:"scope"`
+	Breaking bool   `yaml:"breaking"`
+	Pattern  string `yaml:"pattern"`
+	Bump     string `yaml:"bump"`
+}
+
+// IgnoreConfig defines which commits never contribute to a version bump.
+type IgnoreConfig struct {
+	Types       []string `yaml:"types"`
+	Scopes      []string `yaml:"scopes"`
+	Authors     []string `yaml:"authors"`
+	Paths       []string `yaml:"paths"`
+	SkipMarkers []string `yaml:"skip_markers"`
+}
+
+// ComponentConfig declares an additional numeric version component that is less
+// significant than patch, such as the fourth number of a "1.2.3.4" version.
+// The component must appear in version_format as {name}.
+type ComponentConfig struct {
+	Name string `yaml:"name"`
+	// Triggers lists the commit type entries that bump this component
+	Triggers []string `yaml:"triggers"`
+	// Reset is "higher" to reset the component when a more significant one is bumped,
+	// or "never" to keep counting across releases
+	Reset string `yaml:"reset"`
+}
+
+// SquashConfig holds options for repositories that squash-merge pull requests.
+type SquashConfig struct {
+	// SubjectOnly classifies commits by their subject line (the pull request title) only
+	SubjectOnly bool `yaml:"subject_only"`
+	// BodyEntries lists the bullet points of a commit body as changelog sub-entries
+	BodyEntries bool `yaml:"body_entries"`
 }
 
 // GitConfig holds git-specific configuration options.
 type GitConfig struct {
-	TagPattern string `yaml:"tag_pattern"`
-	AutoPush   bool   `yaml:"auto_push"`
+	TagPattern  string        `yaml:"tag_pattern"`
+	AutoPush    bool          `yaml:"auto_push"`
+	SkipMerges  bool          `yaml:"skip_merges"`
+	FirstParent bool          `yaml:"first_parent"`
+	Backend     string        `yaml:"backend"`
+	Timeouts    TimeoutConfig `yaml:"timeouts"`
+	Shallow     string        `yaml:"shallow"`
+	FetchDepth  int           `yaml:"fetch_depth"`
+	Remote      string        `yaml:"remote"`
+	Push        PushConfig    `yaml:"push"`
+	Signing     SigningConfig `yaml:"signing"`
+	Tag         TagConfig     `yaml:"tag"`
+}
+
+// TagConfig defines the kind of release tags created and their message.
+type TagConfig struct {
+	// Type is "annotated" or "lightweight", lightweight tags have no message
+	Type string `yaml:"type"`
+	// Message is the annotated tag message. {tag}, {version} and {previous} are replaced
+	// by the release tag, its version and the previous release tag, {changelog} by the release notes.
+	Message string `yaml:"message"`
+}
+
+// SigningConfig defines how release tags are signed and verified.
+type SigningConfig struct {
+	Format         string `yaml:"format"`
+	Key            string `yaml:"key"`
+	AllowedSigners string `yaml:"allowed_signers"`
+	Strict         bool   `yaml:"strict"`
+}
+
+// PushConfig defines how tags are pushed when AutoPush is enabled.
+type PushConfig struct {
+	Atomic     bool     `yaml:"atomic"`
+	FollowTags bool     `yaml:"follow_tags"`
+	Refspecs   []string `yaml:"refspecs"`
+}
+
+// TimeoutConfig bounds how long git operations may run, e.g. "30s" or "2m".
+// Operations without a timeout use Default, and zero means no limit.
+type TimeoutConfig struct {
+	Default time.Duration `yaml:"default"`
+	Read    time.Duration `yaml:"read"`
+	Tag     time.Duration `yaml:"tag"`
+	Push    time.Duration `yaml:"push"`
+	Fetch   time.Duration `yaml:"fetch"`
+}
+
+// PreflightConfig defines the checks that must pass before a release changes anything.
+type PreflightConfig struct {
+	// Clean requires a working tree without uncommitted changes
+	Clean bool `yaml:"clean"`
+	// Branches lists the branches releases are allowed from, such as "main" or "release/*".
+	// Any branch is allowed when it is empty.
+	Branches []string `yaml:"branches"`
+	// UpToDate requires HEAD to contain every commit of its upstream branch
+	UpToDate bool `yaml:"up_to_date"`
+	// UniqueTag requires the release tag not to exist locally
+	UniqueTag bool `yaml:"unique_tag"`
+	// RemoteTag requires the release tag not to exist on the remote either, which needs network access
+	RemoteTag bool `yaml:"remote_tag"`
+	// NewerVersion requires the release to be newer than every existing release tag
+	NewerVersion bool `yaml:"newer_version"`
 }
 
 // OutputConfig defines output formatting options.
@@ -44,14 +162,16 @@ type OutputConfig struct {
 
 // PathConfig represents configuration for a specific path in the repository.
 type PathConfig struct {
-	Path           string      `yaml:"path"`
-	VersionPrefix  string      `yaml:"version_prefix"`
-	VersionFormat  string      `yaml:"version_format"`
-	PreRelease     string      `yaml:"pre_release"`
-	BuildMetadata  string      `yaml:"build_metadata"`
-	TagPattern     string      `yaml:"tag_pattern"`
-	DefaultCommand string      `yaml:"default_command"`
-	CommitTypes    CommitTypes `yaml:"commit_types"`
+	Path           string        `yaml:"path"`
+	VersionPrefix  string        `yaml:"version_prefix"`
+	VersionFormat  string        `yaml:"version_format"`
+	PreRelease     string        `yaml:"pre_release"`
+	BuildMetadata  string        `yaml:"build_metadata"`
+	TagPattern     string        `yaml:"tag_pattern"`
+	Dialect        string        `yaml:"dialect"`
+	DefaultCommand CommandConfig `yaml:"default_command"`
+	CommitTypes    CommitTypes   `yaml:"commit_types"`
+	Hooks          HooksConfig   `yaml:"hooks"`
 }
 
 // LoadConfig loads the configuration from various sources and validates it.
@@ -64,12 +184,16 @@ func LoadConfig() (*Config, error) {
 	// Set defaults
 	v.SetDefault("version_format", "{major}.{minor}.{patch}")
 	v.SetDefault("version_prefix", "v")
+	v.SetDefault("preflight.unique_tag", true)
+	v.SetDefault("preflight.newer_version", true)
 	v.SetDefault("commit_types", map[string][]string{
 		"major": {"BREAKING CHANGE"},
 		"minor": {"feat"},
 		"patch": {"fix"},
 	})
 
+	var sources []source
+
 	// Check for config file path in environment variable
 	configPath := os.Getenv("BUMPIT_CONFIG")
 	if configPath != "" {
@@ -78,10 +202,15 @@ func LoadConfig() (*Config, error) {
 		if err != nil {
 			return nil, fmt.Errorf("failed to read config file: %v", err)
 		}
+		if err := validateYAML(configPath, configData); err != nil {
+			return nil, err
+		}
 		if err := v.ReadConfig(strings.NewReader(string(configData))); err != nil {
 			return nil, fmt.Errorf("failed to parse config file: %v", err)
 		}
+		sources = append(sources, newSource("BUMPIT_CONFIG="+configPath, configData))
 	} else {
+		var problems []Problem
 		// Load from default locations
 		v.SetConfigName("default.config")
 		v.AddConfigPath(".")
@@ -92,6 +221,9 @@ func LoadConfig() (*Config, error) {
 			if _, ok := err.(viper.ConfigFileNotFoundError); !ok {
 				return nil, err
 			}
+		} else {
+			problems = append(problems, fileProblems(v.ConfigFileUsed())...)
+			sources = append(sources, readSource(v.ConfigFileUsed()))
 		}
 
 		// Look for and merge with .bumpit.yaml in the current directory
@@ -100,35 +232,124 @@ func LoadConfig() (*Config, error) {
 			if _, ok := err.(viper.ConfigFileNotFoundError); !ok {
 				return nil, err
 			}
+		} else {
+			problems = append(problems, fileProblems(v.ConfigFileUsed())...)
+			sources = append(sources, readSource(v.ConfigFileUsed()))
+		}
+
+		// Report the problems of both files at once
+		if len(problems) > 0 {
+			return nil, &SchemaError{Problems: problems}
 		}
 	}
 
+	var components []ComponentConfig
+	if err := decodeRaw(v.Get("components"), &components); err != nil {
+		return nil, fmt.Errorf("invalid components: %v", err)
+	}
+	if err := validateComponents(components); err != nil {
+		return nil, err
+	}
+	levels := bumpLevels(components)
+
 	// Check raw version format before unmarshaling
 	if v.IsSet("version_format") {
 		rawFormat := v.GetString("version_format")
-		if err := validateVersionFormat(rawFormat); err != nil {
+		if err := validateVersionFormat(rawFormat, components); err != nil {
 			return nil, err
 		}
 	}
 
+	if err := validateCommitTypes(v.Get("commit_types"), levels); err != nil {
+		return nil, err
+	}
+	if err := validateDialect(v.GetString("dialect")); err != nil {
+		return nil, err
+	}
+
 	// Check raw version format for paths before unmarshaling
 	if v.IsSet("paths") {
-		paths := v.Get("paths").([]interface{})
-		for _, path := range paths {
-			pathMap := path.(map[string]interface{})
+		paths, ok := v.Get("paths").([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("invalid paths: must be a list")
+		}
+		for i, path := range paths {
+			pathMap, ok := path.(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf("invalid paths[%d]: must be a mapping", i)
+			}
 			if format, ok := pathMap["version_format"]; ok {
-				if err := validateVersionFormat(format.(string)); err != nil {
+				formatString, ok := format.(string)
+				if !ok {
+					return nil, fmt.Errorf("invalid version format for path %s: must be a string", pathMap["path"])
+				}
+				if err := validateVersionFormat(formatString, components); err != nil {
 					return nil, fmt.Errorf("invalid version format for path %s: %v", pathMap["path"], err)
 				}
 			}
+			if err := validateCommitTypes(pathMap["commit_types"], levels); err != nil {
+				return nil, fmt.Errorf("invalid commit types for path %s: %v", pathMap["path"], err)
+			}
+			if dialect, ok := pathMap["dialect"].(string); ok {
+				if err := validateDialect(dialect); err != nil {
+					return nil, fmt.Errorf("invalid dialect for path %s: %v", pathMap["path"], err)
+				}
+			}
 		}
 	}
 
+	if err := validateZeroMajor(v.GetString("zero_major")); err != nil {
+		return nil, err
+	}
+	if err := validateUnmatchedBump(v.GetString("unmatched_bump"), levels); err != nil {
+		return nil, err
+	}
+	if err := validateBackend(v.GetString("git.backend")); err != nil {
+		return nil, err
+	}
+	if err := validateTimeouts(v.GetStringMap("git.timeouts")); err != nil {
+		return nil, err
+	}
+	if err := validateShallow(v.GetString("git.shallow"), v.GetInt("git.fetch_depth")); err != nil {
+		return nil, err
+	}
+	if err := validatePush(v.GetString("git.remote"), v.GetStringSlice("git.push.refspecs")); err != nil {
+		return nil, err
+	}
+	if err := validateSigning(v.GetString("git.signing.format"), v.GetBool("git.signing.strict"), v.GetString("git.backend")); err != nil {
+		return nil, err
+	}
+	if err := validateTag(v.GetString("git.tag.type"), v.GetString("git.tag.message"), v.GetString("git.signing.format"), v.GetBool("git.signing.strict")); err != nil {
+		return nil, err
+	}
+	if err := validateBranches(v.GetStringSlice("preflight.branches")); err != nil {
+		return nil, err
+	}
+
 	var config Config
-	if err := v.Unmarshal(&config); err != nil {
+	if err := v.Unmarshal(&config, decodeWithYAMLTags); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal config: %v", err)
 	}
 
+	if !config.DefaultCommand.IsZero() {
+		if err := validateCommand(config.DefaultCommand); err != nil {
+			return nil, fmt.Errorf("invalid default_command: %v", err)
+		}
+	}
+	if err := validateHooks(config.Hooks); err != nil {
+		return nil, err
+	}
+	for _, pathConfig := range config.Paths {
+		if !pathConfig.DefaultCommand.IsZero() {
+			if err := validateCommand(pathConfig.DefaultCommand); err != nil {
+				return nil, fmt.Errorf("invalid default_command for path %s: %v", pathConfig.Path, err)
+			}
+		}
+		if err := validateHooks(pathConfig.Hooks); err != nil {
+			return nil, fmt.Errorf("invalid hooks for path %s: %v", pathConfig.Path, err)
+		}
+	}
+
 	// Set default values
 	if config.VersionPrefix == "" {
 		config.VersionPrefix = "v"
@@ -136,6 +357,36 @@ func LoadConfig() (*Config, error) {
 	if config.VersionFormat == "" {
 		config.VersionFormat = "{major}.{minor}.{patch}"
 	}
+	if config.Dialect == "" {
+		config.Dialect = "semver"
+	}
+	if config.ZeroMajor == "" {
+		config.ZeroMajor = "standard"
+	}
+	if config.UnmatchedBump == "" {
+		config.UnmatchedBump = "patch"
+	}
+	if config.Git.Backend == "" {
+		config.Git.Backend = "exec"
+	}
+	if config.Git.Remote == "" {
+		config.Git.Remote = "origin"
+	}
+	if config.Git.Shallow == "" {
+		config.Git.Shallow = "fail"
+	}
+	if config.Git.Tag.Type == "" {
+		config.Git.Tag.Type = "annotated"
+	}
+	if config.Git.Tag.Message == "" {
+		config.Git.Tag.Message = "Release {tag}"
+	}
+	if !v.IsSet("git.fetch_depth") {
+		config.Git.FetchDepth = 50
+	}
+	if config.Ignore.SkipMarkers == nil {
+		config.Ignore.SkipMarkers = []string{"[skip release]"}
+	}
 
 	if len(config.CommitTypes.Major) == 0 {
 		config.CommitTypes.Major = []string{"BREAKING CHANGE"}
@@ -149,50 +400,68 @@ func LoadConfig() (*Config, error) {
 
 	// Set default values for paths
 	for i := range config.Paths {
-		if config.Paths[i].VersionPrefix == "" {
-			config.Paths[i].VersionPrefix = config.VersionPrefix
-		}
-		if config.Paths[i].VersionFormat == "" {
-			config.Paths[i].VersionFormat = config.VersionFormat
-		}
-		if len(config.Paths[i].CommitTypes.Major) == 0 {
-			config.Paths[i].CommitTypes.Major = config.CommitTypes.Major
-		}
-		if len(config.Paths[i].CommitTypes.Minor) == 0 {
-			config.Paths[i].CommitTypes.Minor = config.CommitTypes.Minor
-		}
-		if len(config.Paths[i].CommitTypes.Patch) == 0 {
-			config.Paths[i].CommitTypes.Patch = config.CommitTypes.Patch
-		}
+		config.Paths[i] = config.inherit(config.Paths[i])
 	}
 
+	config.sources = sources
 	return &config, nil
 }
 
-// GetCommitType determines the type of version bump needed based on commit message
+// GetCommitType determines the type of version bump needed based on commit message.
+// It matches like MatchCommitType, so changelogs group commits the way they bump.
 func (c *Config) GetCommitType(commitMsg string) string {
+	commitType, _ := c.MatchCommitType(commitMsg)
+	return commitType
+}
+
+// MatchCommitType determines the type of version bump needed based on commit message
+// and also returns a description of the configured rule that matched it.
+// When nothing matches it returns "none" and an empty rule.
+// With squash.subject_only only the first line of the message is considered.
+// List entries are case-sensitive substrings, so "Feature" does not match "feat".
+func (c *Config) MatchCommitType(commitMsg string) (string, string) {
+	if c.Squash.SubjectOnly {
+		commitMsg = strings.SplitN(commitMsg, "\n", 2)[0]
+	}
+
+	// Structured rules take precedence over the plain prefix lists
+	for _, rule := range c.CommitTypes.Rules {
+		if rule.Matches(commitMsg) {
+			return rule.Bump, rule.String()
+		}
+	}
+
 	// Check major changes
 	for _, prefix := range c.CommitTypes.Major {
-		if hasPrefix(commitMsg, prefix) {
-			return "major"
+		if matchesRule(commitMsg, prefix) {
+			return "major", prefix
 		}
 	}
 
 	// Check minor changes
 	for _, prefix := range c.CommitTypes.Minor {
-		if hasPrefix(commitMsg, prefix) {
-			return "minor"
+		if matchesRule(commitMsg, prefix) {
+			return "minor", prefix
 		}
 	}
 
 	// Check patch changes
 	for _, prefix := range c.CommitTypes.Patch {
-		if hasPrefix(commitMsg, prefix) {
-			return "patch"
+		if matchesRule(commitMsg, prefix) {
+			return "patch", prefix
+		}
+	}
+
+	// Check additional version components, from most to least significant
+	for _, component := range c.Components {
+		for _, prefix := range component.Triggers {
+			if matchesRule(commitMsg, prefix) {
+				return component.Name, prefix
+			}
 		}
 	}
 
-	return "none"
+	return "none", ""
 }
 
 // hasPrefix checks if a message has a specific prefix
@@ -200,15 +469,12 @@ func hasPrefix(msg, prefix string) bool {
 	return strings.Contains(strings.ToLower(msg), strings.ToLower(prefix))
 }
 
-// GetPathConfig returns the configuration for a specific path
+// GetPathConfig returns the configuration for a specific path, with the settings
+// its path configuration leaves empty inherited from the root configuration
 func (c *Config) GetPathConfig(path string) PathConfig {
 	// If no path is provided, return a default config
 	if path == "" {
-		return PathConfig{
-			VersionPrefix: c.VersionPrefix,
-			VersionFormat: c.VersionFormat,
-			CommitTypes:   c.CommitTypes,
-		}
+		return c.inherit(PathConfig{})
 	}
 
 	// Find the most specific path configuration
@@ -232,34 +498,72 @@ func (c *Config) GetPathConfig(path string) PathConfig {
 
 	// If no matching path found, return default config
 	if bestMatchLen == -1 {
-		return PathConfig{
-			VersionPrefix: c.VersionPrefix,
-			VersionFormat: c.VersionFormat,
-			CommitTypes:   c.CommitTypes,
-		}
+		return c.inherit(PathConfig{})
 	}
 
-	// Fill in any missing values with defaults from the main config
-	if bestMatch.VersionPrefix == "" {
-		bestMatch.VersionPrefix = c.VersionPrefix
+	return c.inherit(bestMatch)
+}
+
+// inherit fills the settings a path configuration leaves empty from the root configuration.
+// LoadConfig, GetPathConfig and ForPath all resolve paths with it, so they agree.
+func (c *Config) inherit(p PathConfig) PathConfig {
+	if p.VersionPrefix == "" {
+		p.VersionPrefix = c.VersionPrefix
 	}
-	if bestMatch.VersionFormat == "" {
-		bestMatch.VersionFormat = c.VersionFormat
+	if p.VersionFormat == "" {
+		p.VersionFormat = c.VersionFormat
 	}
-	if len(bestMatch.CommitTypes.Major) == 0 {
-		bestMatch.CommitTypes.Major = c.CommitTypes.Major
+	if p.PreRelease == "" {
+		p.PreRelease = c.PreRelease
 	}
-	if len(bestMatch.CommitTypes.Minor) == 0 {
-		bestMatch.CommitTypes.Minor = c.CommitTypes.Minor
+	if p.BuildMetadata == "" {
+		p.BuildMetadata = c.BuildMetadata
 	}
-	if len(bestMatch.CommitTypes.Patch) == 0 {
-		bestMatch.CommitTypes.Patch = c.CommitTypes.Patch
+	if p.TagPattern == "" {
+		p.TagPattern = c.Git.TagPattern
 	}
+	if p.Dialect == "" {
+		p.Dialect = c.Dialect
+	}
+	if p.DefaultCommand.IsZero() {
+		p.DefaultCommand = c.DefaultCommand
+	}
+	if len(p.CommitTypes.Major) == 0 {
+		p.CommitTypes.Major = c.CommitTypes.Major
+	}
+	if len(p.CommitTypes.Minor) == 0 {
+		p.CommitTypes.Minor = c.CommitTypes.Minor
+	}
+	if len(p.CommitTypes.Patch) == 0 {
+		p.CommitTypes.Patch = c.CommitTypes.Patch
+	}
+	if len(p.CommitTypes.Rules) == 0 {
+		p.CommitTypes.Rules = c.CommitTypes.Rules
+	}
+	p.Hooks = c.Hooks.merge(p.Hooks)
+	return p
+}
+
+// ForPath returns a copy of the configuration with the settings of the path
+// configuration matching path applied, for use by the version package.
+func (c *Config) ForPath(path string) *Config {
+	pathConfig := c.GetPathConfig(path)
 
-	return bestMatch
+	cfg := *c
+	cfg.VersionPrefix = pathConfig.VersionPrefix
+	cfg.VersionFormat = pathConfig.VersionFormat
+	cfg.PreRelease = pathConfig.PreRelease
+	cfg.BuildMetadata = pathConfig.BuildMetadata
+	cfg.Git.TagPattern = pathConfig.TagPattern
+	cfg.Dialect = pathConfig.Dialect
+	cfg.DefaultCommand = pathConfig.DefaultCommand
+	cfg.CommitTypes = pathConfig.CommitTypes
+	cfg.Hooks = pathConfig.Hooks
+	cfg.Paths = nil
+	return &cfg
 }
 
-func validateVersionFormat(format string) error {
+func validateVersionFormat(format string, components []ComponentConfig) error {
 	if format == "" {
 		return nil // Empty format will be replaced with default
 	}
@@ -271,5 +575,226 @@ func validateVersionFormat(format string) error {
 		!strings.Contains(format, "{patch}") {
 		return fmt.Errorf("invalid version format: must contain {major}, {minor}, and {patch}")
 	}
+	for _, component := range components {
+		if !strings.Contains(format, "{"+component.Name+"}") {
+			return fmt.Errorf("invalid version format: must contain {%s} for the %s component", component.Name, component.Name)
+		}
+	}
+	return nil
+}
+
+// componentName matches valid names for additional version components
+var componentName = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+// validateComponents checks the names and reset rules of additional version components
+func validateComponents(components []ComponentConfig) error {
+	seen := make(map[string]bool)
+	for _, component := range components {
+		switch {
+		case !componentName.MatchString(component.Name):
+			return fmt.Errorf("invalid component name %q: must be lowercase letters, digits or underscores", component.Name)
+		case component.Name == "major" || component.Name == "minor" || component.Name == "patch" || component.Name == "none":
+			return fmt.Errorf("invalid component name %q: reserved bump level", component.Name)
+		case seen[component.Name]:
+			return fmt.Errorf("duplicate component %q", component.Name)
+		}
+		seen[component.Name] = true
+
+		switch component.Reset {
+		case "", "higher", "never":
+		default:
+			return fmt.Errorf("invalid reset %q for component %s: must be higher or never", component.Reset, component.Name)
+		}
+	}
+	return nil
+}
+
+// bumpLevels returns every bump level a commit can trigger, from most to least significant
+func bumpLevels(components []ComponentConfig) []string {
+	levels := []string{"major", "minor", "patch"}
+	for _, component := range components {
+		levels = append(levels, component.Name)
+	}
+	return levels
+}
+
+// BumpLevels returns every bump level a commit can trigger, from most to least significant
+func (c *Config) BumpLevels() []string {
+	return bumpLevels(c.Components)
+}
+
+// validateDialect checks the syntax used to render and parse versions
+func validateDialect(dialect string) error {
+	switch dialect {
+	case "", "semver", "pep440", "maven":
+		return nil
+	}
+	return fmt.Errorf("invalid dialect %q: must be semver, pep440 or maven", dialect)
+}
+
+// validateZeroMajor checks the policy used for breaking changes and features while the major version is 0.
+// "standard" follows SemVer and releases 1.0.0 on a breaking change,
+// "shift" bumps minor for breaking changes and patch for features instead.
+func validateZeroMajor(policy string) error {
+	switch policy {
+	case "", "standard", "shift":
+		return nil
+	}
+	return fmt.Errorf("invalid zero_major policy %q: must be standard or shift", policy)
+}
+
+// validateUnmatchedBump checks the bump applied when commits exist but none matches a commit type.
+// It must be one of the bump levels, or "none" meaning such commits do not produce a release.
+func validateUnmatchedBump(bump string, levels []string) error {
+	if bump == "" || bump == "none" || contains(levels, bump) {
+		return nil
+	}
+	return fmt.Errorf("invalid unmatched_bump %q: must be one of %s or none", bump, strings.Join(levels, ", "))
+}
+
+// validateBackend checks the git implementation used to read and tag the repository.
+// "exec" runs the git binary, "go-git" works without git installed.
+func validateBackend(backend string) error {
+	switch backend {
+	case "", "exec", "go-git":
+		return nil
+	}
+	return fmt.Errorf("invalid git backend %q: must be exec or go-git", backend)
+}
+
+// validateTimeouts checks the git operation timeouts are non-negative durations such as "30s"
+func validateTimeouts(timeouts map[string]interface{}) error {
+	for op, value := range timeouts {
+		switch op {
+		case "default", "read", "tag", "push", "fetch":
+		default:
+			return fmt.Errorf("invalid git timeout %q: must be default, read, tag, push or fetch", op)
+		}
+		s, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("invalid git timeout for %s: must be a duration such as \"30s\", got %v", op, value)
+		}
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return fmt.Errorf("invalid git timeout for %s: %v", op, err)
+		}
+		if d < 0 {
+			return fmt.Errorf("invalid git timeout for %s: must not be negative", op)
+		}
+	}
+	return nil
+}
+
+// validateShallow checks the policy for shallow clones whose history misses the latest release tag.
+// "fail" stops with a diagnostic, "fetch" deepens the clone fetch_depth commits at a time
+// (all at once when it is 0) and "ignore" uses the history as it is.
+func validateShallow(policy string, depth int) error {
+	switch policy {
+	case "", "fail", "fetch", "ignore":
+	default:
+		return fmt.Errorf("invalid git shallow policy %q: must be fail, fetch or ignore", policy)
+	}
+	if depth < 0 {
+		return fmt.Errorf("invalid git fetch_depth %d: must not be negative", depth)
+	}
+	return nil
+}
+
+// validatePush checks the remote name and the refspecs pushed together with release tags
+func validatePush(remote string, refspecs []string) error {
+	if strings.ContainsAny(remote, " \t") {
+		return fmt.Errorf("invalid git remote %q: must not contain whitespace", remote)
+	}
+	for _, refspec := range refspecs {
+		if strings.TrimPrefix(refspec, "+") == "" || strings.ContainsAny(refspec, " \t") {
+			return fmt.Errorf("invalid push refspec %q", refspec)
+		}
+	}
+	return nil
+}
+
+// validateSigning checks the tag signing format. Signing and strict verification
+// need the git binary, so they cannot be combined with the go-git backend.
+func validateSigning(format string, strict bool, backend string) error {
+	switch format {
+	case "", "gpg", "ssh":
+	default:
+		return fmt.Errorf("invalid signing format %q: must be gpg or ssh", format)
+	}
+	if (format != "" || strict) && backend == "go-git" {
+		return fmt.Errorf("signed tags require the exec git backend")
+	}
+	return nil
+}
+
+// tagPlaceholder matches a placeholder such as {changelog} in a tag message
+var tagPlaceholder = regexp.MustCompile(`\{(\w+)\}`)
+
+// validateTag checks the tag type and the placeholders of the tag message.
+// Signed tags carry a message, so they cannot be lightweight, and strict verification
+// would reject every lightweight release tag as unsigned.
+func validateTag(tagType, message, signingFormat string, strict bool) error {
+	switch tagType {
+	case "", "annotated", "lightweight":
+	default:
+		return fmt.Errorf("invalid git tag type %q: must be annotated or lightweight", tagType)
+	}
+	if tagType == "lightweight" && signingFormat != "" {
+		return fmt.Errorf("signed tags cannot be lightweight")
+	}
+	if tagType == "lightweight" && strict {
+		return fmt.Errorf("git.signing.strict cannot be used with lightweight tags, which are never signed")
+	}
+	for _, m := range tagPlaceholder.FindAllStringSubmatch(message, -1) {
+		if !contains([]string{"tag", "version", "previous", "changelog"}, m[1]) {
+			return fmt.Errorf("invalid git tag message: unknown placeholder %s, must be {tag}, {version}, {previous} or {changelog}", m[0])
+		}
+	}
+	return nil
+}
+
+// validateBranches checks the glob patterns of the branches releases are allowed from
+func validateBranches(branches []string) error {
+	for _, branch := range branches {
+		if _, err := path.Match(branch, ""); err != nil || strings.TrimSpace(branch) == "" {
+			return fmt.Errorf("invalid preflight branch pattern %q", branch)
+		}
+	}
 	return nil
 }
+
+// contains reports whether list holds s
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+// decodeRaw decodes a raw configuration value using the yaml struct tags
+func decodeRaw(raw interface{}, out interface{}) error {
+	if raw == nil {
+		return nil
+	}
+	decoder, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{TagName: "yaml", Result: out})
+	if err != nil {
+		return err
+	}
+	return decoder.Decode(raw)
+}
+
+// decodeWithYAMLTags makes viper decode keys using the yaml struct tags.
+// It keeps viper's default hooks, which decode durations such as "30s" and comma separated lists,
+// and decodes commands written as a string or a list of arguments. A string is never split
+// at commas when it is decoded into a list of commands.
+func decodeWithYAMLTags(dc *mapstructure.DecoderConfig) {
+	dc.TagName = "yaml"
+	dc.DecodeHook = mapstructure.ComposeDecodeHookFunc(
+		mapstructure.StringToTimeDurationHookFunc(),
+		// Runs before the comma split, so commas stay inside a single command line
+		stringToCommandHookFunc(),
+		mapstructure.StringToSliceHookFunc(","),
+	)
+}
@@ -3,7 +3,10 @@ package config
 import (
 	"os"
 	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestLoadConfig(t *testing.T) {
@@ -165,3 +168,627 @@ version_format: "x.y.z"
 		})
 	}
 }
+
+func TestMatchCommitType(t *testing.T) {
+	cfg := &Config{
+		CommitTypes: CommitTypes{
+			Major: []string{"BREAKING CHANGE"},
+			Minor: []string{"feat"},
+			Patch: []string{"fix", "perf"},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		msg      string
+		wantType string
+		wantRule string
+	}{
+		{"major wins over minor", "feat: x\n\nBREAKING CHANGE: y", "major", "BREAKING CHANGE"},
+		{"minor", "feat(api): add endpoint", "minor", "feat"},
+		{"second patch prefix", "perf: faster", "patch", "perf"},
+		{"no match", "chore: deps", "none", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotType, gotRule := cfg.MatchCommitType(tt.msg)
+			if gotType != tt.wantType || gotRule != tt.wantRule {
+				t.Errorf("MatchCommitType() = (%v, %v), want (%v, %v)", gotType, gotRule, tt.wantType, tt.wantRule)
+			}
+			if got := cfg.GetCommitType(tt.msg); got != tt.wantType {
+				t.Errorf("GetCommitType() = %v, want %v", got, tt.wantType)
+			}
+		})
+	}
+
+	// Bumps and changelog grouping both match list entries case-sensitively
+	for _, msg := range []string{"Feat: add endpoint", "FIX: typo", "Perf: faster"} {
+		if got, _ := cfg.MatchCommitType(msg); got != "none" {
+			t.Errorf("MatchCommitType(%q) = %v, want none", msg, got)
+		}
+		if got := cfg.GetCommitType(msg); got != "none" {
+			t.Errorf("GetCommitType(%q) = %v, want none", msg, got)
+		}
+	}
+}
+
+func TestLoadConfigZeroMajor(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	tests := []struct {
+		name    string
+		config  string
+		want    string
+		wantErr bool
+	}{
+		{
+			name:   "defaults to standard",
+			config: "version_prefix: \"v\"\n",
+			want:   "standard",
+		},
+		{
+			name:   "shift policy",
+			config: "zero_major: \"shift\"\n",
+			want:   "shift",
+		},
+		{
+			name:    "unknown policy",
+			config:  "zero_major: \"sideways\"\n",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			configPath := filepath.Join(tmpDir, "config.yaml")
+			if err := os.WriteFile(configPath, []byte(tt.config), 0644); err != nil {
+				t.Fatalf("Failed to write config file: %v", err)
+			}
+
+			os.Setenv("BUMPIT_CONFIG", configPath)
+			defer os.Unsetenv("BUMPIT_CONFIG")
+
+			got, err := LoadConfig()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("LoadConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && got.ZeroMajor != tt.want {
+				t.Errorf("LoadConfig() zero major = %v, want %v", got.ZeroMajor, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadConfigBackend(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	tests := []struct {
+		name    string
+		config  string
+		want    string
+		wantErr bool
+	}{
+		{
+			name:   "defaults to exec",
+			config: "version_prefix: \"v\"\n",
+			want:   "exec",
+		},
+		{
+			name:   "go-git backend",
+			config: "git:\n  backend: \"go-git\"\n",
+			want:   "go-git",
+		},
+		{
+			name:    "unknown backend",
+			config:  "git:\n  backend: \"libgit2\"\n",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			configPath := filepath.Join(tmpDir, "config.yaml")
+			if err := os.WriteFile(configPath, []byte(tt.config), 0644); err != nil {
+				t.Fatalf("Failed to write config file: %v", err)
+			}
+
+			os.Setenv("BUMPIT_CONFIG", configPath)
+			defer os.Unsetenv("BUMPIT_CONFIG")
+
+			got, err := LoadConfig()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("LoadConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && got.Git.Backend != tt.want {
+				t.Errorf("LoadConfig() git backend = %v, want %v", got.Git.Backend, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadConfigTimeouts(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	tests := []struct {
+		name    string
+		config  string
+		want    TimeoutConfig
+		wantErr bool
+	}{
+		{
+			name:   "no limits by default",
+			config: "version_prefix: \"v\"\n",
+		},
+		{
+			name:   "per operation timeouts",
+			config: "git:\n  timeouts:\n    default: \"30s\"\n    push: \"2m\"\n    fetch: \"5m\"\n",
+			want:   TimeoutConfig{Default: 30 * time.Second, Push: 2 * time.Minute, Fetch: 5 * time.Minute},
+		},
+		{
+			name:    "unknown operation",
+			config:  "git:\n  timeouts:\n    clone: \"30s\"\n",
+			wantErr: true,
+		},
+		{
+			name:    "number without unit",
+			config:  "git:\n  timeouts:\n    push: 30\n",
+			wantErr: true,
+		},
+		{
+			name:    "negative duration",
+			config:  "git:\n  timeouts:\n    read: \"-1s\"\n",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			configPath := filepath.Join(tmpDir, "config.yaml")
+			if err := os.WriteFile(configPath, []byte(tt.config), 0644); err != nil {
+				t.Fatalf("Failed to write config file: %v", err)
+			}
+
+			os.Setenv("BUMPIT_CONFIG", configPath)
+			defer os.Unsetenv("BUMPIT_CONFIG")
+
+			got, err := LoadConfig()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("LoadConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && got.Git.Timeouts != tt.want {
+				t.Errorf("LoadConfig() git timeouts = %+v, want %+v", got.Git.Timeouts, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadConfigShallow(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	tests := []struct {
+		name      string
+		config    string
+		want      string
+		wantDepth int
+		wantErr   bool
+	}{
+		{
+			name:      "defaults to fail",
+			config:    "version_prefix: \"v\"\n",
+			want:      "fail",
+			wantDepth: 50,
+		},
+		{
+			name:      "fetch full history",
+			config:    "git:\n  shallow: \"fetch\"\n  fetch_depth: 0\n",
+			want:      "fetch",
+			wantDepth: 0,
+		},
+		{
+			name:    "unknown policy",
+			config:  "git:\n  shallow: \"deepen\"\n",
+			wantErr: true,
+		},
+		{
+			name:    "negative depth",
+			config:  "git:\n  fetch_depth: -1\n",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			configPath := filepath.Join(tmpDir, "config.yaml")
+			if err := os.WriteFile(configPath, []byte(tt.config), 0644); err != nil {
+				t.Fatalf("Failed to write config file: %v", err)
+			}
+
+			os.Setenv("BUMPIT_CONFIG", configPath)
+			defer os.Unsetenv("BUMPIT_CONFIG")
+
+			got, err := LoadConfig()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("LoadConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if got.Git.Shallow != tt.want || got.Git.FetchDepth != tt.wantDepth {
+				t.Errorf("LoadConfig() shallow = %v, fetch depth = %v, want %v, %v", got.Git.Shallow, got.Git.FetchDepth, tt.want, tt.wantDepth)
+			}
+		})
+	}
+}
+
+func TestLoadConfigPush(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	data := `
+git:
+  auto_push: true
+  remote: "upstream"
+  push:
+    atomic: true
+    follow_tags: true
+    refspecs: ["HEAD"]
+`
+	if err := os.WriteFile(configPath, []byte(data), 0644); err != nil {
+		t.Fatalf("Failed to write config file: %v", err)
+	}
+	os.Setenv("BUMPIT_CONFIG", configPath)
+	defer os.Unsetenv("BUMPIT_CONFIG")
+
+	cfg, err := LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	if cfg.Git.Remote != "upstream" || !cfg.Git.Push.Atomic || !cfg.Git.Push.FollowTags {
+		t.Errorf("LoadConfig() git = %+v", cfg.Git)
+	}
+	if len(cfg.Git.Push.Refspecs) != 1 || cfg.Git.Push.Refspecs[0] != "HEAD" {
+		t.Errorf("LoadConfig() push refspecs = %v, want [HEAD]", cfg.Git.Push.Refspecs)
+	}
+
+	if err := os.WriteFile(configPath, []byte("git:\n  push:\n    refspecs: [\"HEAD main\"]\n"), 0644); err != nil {
+		t.Fatalf("Failed to write config file: %v", err)
+	}
+	if _, err := LoadConfig(); err == nil {
+		t.Errorf("LoadConfig() expected error for a refspec with whitespace")
+	}
+
+	if err := os.WriteFile(configPath, []byte("version_prefix: \"v\"\n"), 0644); err != nil {
+		t.Fatalf("Failed to write config file: %v", err)
+	}
+	cfg, err = LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	if cfg.Git.Remote != "origin" {
+		t.Errorf("LoadConfig() default remote = %v, want origin", cfg.Git.Remote)
+	}
+}
+
+func TestLoadConfigSigning(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	tests := []struct {
+		name    string
+		config  string
+		want    SigningConfig
+		wantErr bool
+	}{
+		{
+			name:   "unsigned by default",
+			config: "version_prefix: \"v\"\n",
+		},
+		{
+			name:   "ssh signing with strict verification",
+			config: "git:\n  signing:\n    format: \"ssh\"\n    key: \"~/.ssh/release\"\n    allowed_signers: \".github/allowed_signers\"\n    strict: true\n",
+			want:   SigningConfig{Format: "ssh", Key: "~/.ssh/release", AllowedSigners: ".github/allowed_signers", Strict: true},
+		},
+		{
+			name:    "unknown format",
+			config:  "git:\n  signing:\n    format: \"x509\"\n",
+			wantErr: true,
+		},
+		{
+			name:    "go-git backend",
+			config:  "git:\n  backend: \"go-git\"\n  signing:\n    strict: true\n",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			configPath := filepath.Join(tmpDir, "config.yaml")
+			if err := os.WriteFile(configPath, []byte(tt.config), 0644); err != nil {
+				t.Fatalf("Failed to write config file: %v", err)
+			}
+
+			os.Setenv("BUMPIT_CONFIG", configPath)
+			defer os.Unsetenv("BUMPIT_CONFIG")
+
+			got, err := LoadConfig()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("LoadConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && got.Git.Signing != tt.want {
+				t.Errorf("LoadConfig() signing = %+v, want %+v", got.Git.Signing, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadConfigPreflight(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	tests := []struct {
+		name    string
+		config  string
+		want    PreflightConfig
+		wantErr bool
+	}{
+		{
+			name:   "tag checks by default",
+			config: "version_prefix: \"v\"\n",
+			want:   PreflightConfig{UniqueTag: true, NewerVersion: true},
+		},
+		{
+			name:   "all checks",
+			config: "preflight:\n  clean: true\n  branches: [\"main\", \"release/*\"]\n  up_to_date: true\n",
+			want:   PreflightConfig{Clean: true, Branches: []string{"main", "release/*"}, UpToDate: true, UniqueTag: true, NewerVersion: true},
+		},
+		{
+			name:   "tag checks disabled",
+			config: "preflight:\n  unique_tag: false\n  newer_version: false\n",
+			want:   PreflightConfig{},
+		},
+		{
+			name:    "invalid branch pattern",
+			config:  "preflight:\n  branches: [\"release/[\"]\n",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			configPath := filepath.Join(tmpDir, "config.yaml")
+			if err := os.WriteFile(configPath, []byte(tt.config), 0644); err != nil {
+				t.Fatalf("Failed to write config file: %v", err)
+			}
+
+			os.Setenv("BUMPIT_CONFIG", configPath)
+			defer os.Unsetenv("BUMPIT_CONFIG")
+
+			got, err := LoadConfig()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("LoadConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && !reflect.DeepEqual(got.Preflight, tt.want) {
+				t.Errorf("LoadConfig() preflight = %+v, want %+v", got.Preflight, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadConfigTag(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	tests := []struct {
+		name    string
+		config  string
+		want    TagConfig
+		wantErr bool
+	}{
+		{
+			name:   "annotated by default",
+			config: "version_prefix: \"v\"\n",
+			want:   TagConfig{Type: "annotated", Message: "Release {tag}"},
+		},
+		{
+			name:   "message with release notes",
+			config: "git:\n  tag:\n    message: \"{tag}\\n\\n{changelog}\"\n",
+			want:   TagConfig{Type: "annotated", Message: "{tag}\n\n{changelog}"},
+		},
+		{
+			name:   "lightweight",
+			config: "git:\n  tag:\n    type: \"lightweight\"\n",
+			want:   TagConfig{Type: "lightweight", Message: "Release {tag}"},
+		},
+		{
+			name:    "unknown type",
+			config:  "git:\n  tag:\n    type: \"signed\"\n",
+			wantErr: true,
+		},
+		{
+			name:    "unknown placeholder",
+			config:  "git:\n  tag:\n    message: \"Release {date}\"\n",
+			wantErr: true,
+		},
+		{
+			name:    "signed lightweight tag",
+			config:  "git:\n  signing:\n    format: \"gpg\"\n  tag:\n    type: \"lightweight\"\n",
+			wantErr: true,
+		},
+		{
+			name:    "strict verification of lightweight tags",
+			config:  "git:\n  signing:\n    strict: true\n  tag:\n    type: \"lightweight\"\n",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			configPath := filepath.Join(tmpDir, "config.yaml")
+			if err := os.WriteFile(configPath, []byte(tt.config), 0644); err != nil {
+				t.Fatalf("Failed to write config file: %v", err)
+			}
+
+			os.Setenv("BUMPIT_CONFIG", configPath)
+			defer os.Unsetenv("BUMPIT_CONFIG")
+
+			got, err := LoadConfig()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("LoadConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && got.Git.Tag != tt.want {
+				t.Errorf("LoadConfig() tag = %+v, want %+v", got.Git.Tag, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadConfigCommitRules(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	data := `
+commit_types:
+  minor: ["feat"]
+  rules:
+    - type: feat
+      scope: docs
+      bump: patch
+paths:
+  - path: "web"
+`
+	if err := os.WriteFile(configPath, []byte(data), 0644); err != nil {
+		t.Fatalf("Failed to write config file: %v", err)
+	}
+
+	os.Setenv("BUMPIT_CONFIG", configPath)
+	defer os.Unsetenv("BUMPIT_CONFIG")
+
+	got, err := LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	if len(got.CommitTypes.Rules) != 1 || got.CommitTypes.Rules[0].Scope != "docs" {
+		t.Fatalf("LoadConfig() rules = %+v", got.CommitTypes.Rules)
+	}
+	if commitType := got.GetPathConfig("web").CommitTypes.Rules[0].Bump; commitType != "patch" {
+		t.Errorf("GetPathConfig() inherited rule bump = %v, want patch", commitType)
+	}
+	if commitType := got.GetCommitType("feat(docs): guide"); commitType != "patch" {
+		t.Errorf("GetCommitType() = %v, want patch", commitType)
+	}
+}
+
+func TestLoadConfigComponents(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	tests := []struct {
+		name        string
+		config      string
+		wantErr     bool
+		errContains string
+	}{
+		{
+			name: "four component version",
+			config: `
+version_format: "{major}.{minor}.{patch}.{build}"
+components:
+  - name: build
+    triggers: ["build"]
+unmatched_bump: build
+commit_types:
+  rules:
+    - type: ci
+      bump: build
+`,
+		},
+		{
+			name: "component missing from format",
+			config: `
+version_format: "{major}.{minor}.{patch}"
+components:
+  - name: build
+`,
+			wantErr:     true,
+			errContains: "must contain {build}",
+		},
+		{
+			name: "reserved name",
+			config: `
+version_format: "{major}.{minor}.{patch}.{patch}"
+components:
+  - name: patch
+`,
+			wantErr:     true,
+			errContains: "reserved bump level",
+		},
+		{
+			name: "invalid reset",
+			config: `
+version_format: "{major}.{minor}.{patch}.{build}"
+components:
+  - name: build
+    reset: sometimes
+`,
+			wantErr:     true,
+			errContains: "invalid reset",
+		},
+		{
+			name:        "rule bumping unknown component",
+			config:      "commit_types:\n  rules:\n    - type: ci\n      bump: build\n",
+			wantErr:     true,
+			errContains: "invalid bump",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			configPath := filepath.Join(tmpDir, "config.yaml")
+			if err := os.WriteFile(configPath, []byte(tt.config), 0644); err != nil {
+				t.Fatalf("Failed to write config file: %v", err)
+			}
+
+			os.Setenv("BUMPIT_CONFIG", configPath)
+			defer os.Unsetenv("BUMPIT_CONFIG")
+
+			got, err := LoadConfig()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("LoadConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				if !strings.Contains(err.Error(), tt.errContains) {
+					t.Errorf("LoadConfig() error = %v, want error containing %v", err, tt.errContains)
+				}
+				return
+			}
+			if levels := got.BumpLevels(); len(levels) != 4 || levels[3] != "build" {
+				t.Errorf("BumpLevels() = %v", levels)
+			}
+			if commitType := got.GetCommitType("build: rebuild"); commitType != "build" {
+				t.Errorf("GetCommitType() = %v, want build", commitType)
+			}
+		})
+	}
+}
+
+func TestForPath(t *testing.T) {
+	cfg := &Config{
+		VersionPrefix: "v",
+		VersionFormat: "{major}.{minor}.{patch}",
+		Dialect:       "semver",
+		PreRelease:    "rc.1",
+		Git:           GitConfig{TagPattern: "v*"},
+		Paths: []PathConfig{
+			{Path: "python/sdk", Dialect: "pep440", TagPattern: "sdk/v*"},
+			{Path: "java"},
+		},
+	}
+
+	sdk := cfg.ForPath("python/sdk/src")
+	if sdk.Dialect != "pep440" || sdk.Git.TagPattern != "sdk/v*" || sdk.PreRelease != "rc.1" {
+		t.Errorf("ForPath() = dialect %v, tag pattern %v, pre-release %v", sdk.Dialect, sdk.Git.TagPattern, sdk.PreRelease)
+	}
+	if sdk.Paths != nil {
+		t.Errorf("ForPath() should not carry path configurations")
+	}
+
+	if java := cfg.ForPath("java"); java.Dialect != "semver" || java.Git.TagPattern != "v*" {
+		t.Errorf("ForPath() = dialect %v, tag pattern %v, want inherited values", java.Dialect, java.Git.TagPattern)
+	}
+	if cfg.Dialect != "semver" {
+		t.Errorf("ForPath() modified the root configuration")
+	}
+}
@@ -0,0 +1,70 @@
+package config
+
+import "fmt"
+
+// HooksConfig lists the commands run at each stage of a release, in order.
+type HooksConfig struct {
+	PreBump  []CommandConfig `yaml:"pre_bump"`
+	PostBump []CommandConfig `yaml:"post_bump"`
+	PreTag   []CommandConfig `yaml:"pre_tag"`
+	PostTag  []CommandConfig `yaml:"post_tag"`
+	PostPush []CommandConfig `yaml:"post_push"`
+}
+
+// Hook stages in the order they run during a release
+const (
+	HookPreBump  = "pre_bump"
+	HookPostBump = "post_bump"
+	HookPreTag   = "pre_tag"
+	HookPostTag  = "post_tag"
+	HookPostPush = "post_push"
+)
+
+// Stage returns the hooks of a stage such as HookPreTag
+func (h HooksConfig) Stage(stage string) []CommandConfig {
+	switch stage {
+	case HookPreBump:
+		return h.PreBump
+	case HookPostBump:
+		return h.PostBump
+	case HookPreTag:
+		return h.PreTag
+	case HookPostTag:
+		return h.PostTag
+	case HookPostPush:
+		return h.PostPush
+	}
+	return nil
+}
+
+// merge returns the hooks with every stage that override sets replaced
+func (h HooksConfig) merge(override HooksConfig) HooksConfig {
+	if len(override.PreBump) > 0 {
+		h.PreBump = override.PreBump
+	}
+	if len(override.PostBump) > 0 {
+		h.PostBump = override.PostBump
+	}
+	if len(override.PreTag) > 0 {
+		h.PreTag = override.PreTag
+	}
+	if len(override.PostTag) > 0 {
+		h.PostTag = override.PostTag
+	}
+	if len(override.PostPush) > 0 {
+		h.PostPush = override.PostPush
+	}
+	return h
+}
+
+// validateHooks checks every hook is a valid command
+func validateHooks(hooks HooksConfig) error {
+	for _, stage := range []string{HookPreBump, HookPostBump, HookPreTag, HookPostTag, HookPostPush} {
+		for i, hook := range hooks.Stage(stage) {
+			if err := validateCommand(hook); err != nil {
+				return fmt.Errorf("invalid %s hook %d: %v", stage, i+1, err)
+			}
+		}
+	}
+	return nil
+}
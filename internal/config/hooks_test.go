@@ -0,0 +1,221 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestLoadConfigHooks(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	tests := []struct {
+		name    string
+		config  string
+		want    HooksConfig
+		wantErr bool
+	}{
+		{
+			name:   "no hooks",
+			config: "version_prefix: \"v\"\n",
+		},
+		{
+			name:   "plain strings and structured hooks",
+			config: "hooks:\n  pre_tag:\n    - \"make build\"\n    - run: \"make test\"\n      dir: \"sdk\"\n      timeout: \"5m\"\n  post_push: [\"./notify.sh\"]\n",
+			want: HooksConfig{
+				PreTag: []CommandConfig{
+					{Run: "make build"},
+					{Run: "make test", Dir: "sdk", Timeout: 5 * time.Minute},
+				},
+				PostPush: []CommandConfig{{Run: "./notify.sh"}},
+			},
+		},
+		{
+			name:   "argv and shell commands",
+			config: "hooks:\n  pre_bump:\n    - [\"npm\", \"version\", \"${version}\"]\n    - args: [\"make\", \"build\"]\n    - run: \"make test && make lint\"\n      shell: true\n",
+			want: HooksConfig{
+				PreBump: []CommandConfig{
+					{Args: []string{"npm", "version", "${version}"}},
+					{Args: []string{"make", "build"}},
+					{Run: "make test && make lint", Shell: true},
+				},
+			},
+		},
+		{
+			name:   "single command with a comma",
+			config: "hooks:\n  pre_tag: \"echo a,b\"\n  post_tag:\n    - \"echo c,d\"\n",
+			want: HooksConfig{
+				PreTag:  []CommandConfig{{Run: "echo a,b"}},
+				PostTag: []CommandConfig{{Run: "echo c,d"}},
+			},
+		},
+		{
+			name:    "run and args",
+			config:  "hooks:\n  pre_tag:\n    - run: \"make\"\n      args: [\"make\"]\n",
+			wantErr: true,
+		},
+		{
+			name:    "shell with args",
+			config:  "hooks:\n  pre_tag:\n    - args: [\"make\"]\n      shell: true\n",
+			wantErr: true,
+		},
+		{
+			name:    "empty command",
+			config:  "hooks:\n  post_tag:\n    - dir: \"sdk\"\n",
+			wantErr: true,
+		},
+		{
+			name:    "empty command for a path",
+			config:  "paths:\n  - path: \"sdk\"\n    hooks:\n      pre_bump:\n        - \"\"\n",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			configPath := filepath.Join(tmpDir, "config.yaml")
+			if err := os.WriteFile(configPath, []byte(tt.config), 0644); err != nil {
+				t.Fatalf("Failed to write config file: %v", err)
+			}
+
+			os.Setenv("BUMPIT_CONFIG", configPath)
+			defer os.Unsetenv("BUMPIT_CONFIG")
+
+			got, err := LoadConfig()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("LoadConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && !reflect.DeepEqual(got.Hooks, tt.want) {
+				t.Errorf("LoadConfig() hooks = %+v, want %+v", got.Hooks, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadConfigDefaultCommand(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	tests := []struct {
+		name    string
+		config  string
+		want    CommandConfig
+		wantErr bool
+	}{
+		{
+			name:   "command line",
+			config: "default_command: \"git tag ${tag}\"\n",
+			want:   CommandConfig{Run: "git tag ${tag}"},
+		},
+		{
+			name:   "argv",
+			config: "default_command: [\"npm\", \"version\", \"${version}\"]\n",
+			want:   CommandConfig{Args: []string{"npm", "version", "${version}"}},
+		},
+		{
+			name:   "shell opt-in",
+			config: "default_command:\n  run: \"cd sdk && npm version ${version}\"\n  shell: true\n",
+			want:   CommandConfig{Run: "cd sdk && npm version ${version}", Shell: true},
+		},
+		{
+			name:    "empty program",
+			config:  "default_command: [\"\", \"version\"]\n",
+			wantErr: true,
+		},
+		{
+			name:    "shell operators without shell",
+			config:  "default_command: \"cd sdk && npm version ${version}\"\n",
+			wantErr: true,
+		},
+		{
+			name:    "line continuation without shell",
+			config:  "default_command: |\n  npm version ${version} \\\n    --no-git-tag-version\n",
+			wantErr: true,
+		},
+		{
+			name:   "quoted operators",
+			config: "default_command: \"git commit -m 'a && b'\"\n",
+			want:   CommandConfig{Run: "git commit -m 'a && b'"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			configPath := filepath.Join(tmpDir, "config.yaml")
+			if err := os.WriteFile(configPath, []byte(tt.config), 0644); err != nil {
+				t.Fatalf("Failed to write config file: %v", err)
+			}
+
+			os.Setenv("BUMPIT_CONFIG", configPath)
+			defer os.Unsetenv("BUMPIT_CONFIG")
+
+			got, err := LoadConfig()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("LoadConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && !reflect.DeepEqual(got.DefaultCommand, tt.want) {
+				t.Errorf("LoadConfig() default_command = %+v, want %+v", got.DefaultCommand, tt.want)
+			}
+		})
+	}
+}
+
+func TestForPathHooks(t *testing.T) {
+	cfg := &Config{
+		Hooks: HooksConfig{
+			PreTag:   []CommandConfig{{Run: "make build"}},
+			PostPush: []CommandConfig{{Run: "./notify.sh"}},
+		},
+		Paths: []PathConfig{
+			{Path: "sdk", Hooks: HooksConfig{PreTag: []CommandConfig{{Run: "npm run build", Dir: "sdk"}}}},
+		},
+	}
+
+	sdk := cfg.ForPath("sdk")
+	if got := sdk.Hooks.Stage(HookPreTag); !reflect.DeepEqual(got, []CommandConfig{{Run: "npm run build", Dir: "sdk"}}) {
+		t.Errorf("ForPath() pre_tag hooks = %+v, want the path hooks", got)
+	}
+	if got := sdk.Hooks.Stage(HookPostPush); !reflect.DeepEqual(got, cfg.Hooks.PostPush) {
+		t.Errorf("ForPath() post_push hooks = %+v, want the inherited hooks", got)
+	}
+	if got := cfg.Hooks.Stage(HookPreTag); got[0].Run != "make build" {
+		t.Errorf("ForPath() modified the root hooks")
+	}
+}
+
+func TestShellOperator(t *testing.T) {
+	tests := map[string]string{
+		"npm version ${version}":            "",
+		"git commit -m 'fix: a | b; c'":     "",
+		`echo "a > b" \&\& true`:            "",
+		"make test && make lint":            "&&",
+		"make test || true":                 "||",
+		"git log | head":                    "|",
+		"make; make install":                ";",
+		"echo ${version} > VERSION":         ">",
+		"echo $(date)":                      "$(",
+		`echo "$(date)"`:                    "$(",
+		"echo `date`":                       "`",
+		"npm version \\\n  ${version}":      "\\",
+		"./release.sh 2>&1":                 ">",
+		"sleep 5 &":                         "&",
+		"git commit -m \"it's ${version}\"": "",
+	}
+	for line, want := range tests {
+		if got := shellOperator(line); got != want {
+			t.Errorf("shellOperator(%q) = %q, want %q", line, got, want)
+		}
+	}
+}
+
+func TestStringToCommandHookFunc(t *testing.T) {
+	decode := stringToCommandHookFunc()
+	if _, err := decode(reflect.TypeOf([]int{}), reflect.TypeOf(CommandConfig{}), []int{1, 2}); err == nil {
+		t.Error("stringToCommandHookFunc() decoded a list of integers without an error")
+	}
+	got, err := decode(reflect.TypeOf([]string{}), reflect.TypeOf(CommandConfig{}), []string{"make", "test"})
+	if err != nil || !reflect.DeepEqual(got, CommandConfig{Args: []string{"make", "test"}}) {
+		t.Errorf("stringToCommandHookFunc() = %+v, %v", got, err)
+	}
+}
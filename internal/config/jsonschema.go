@@ -0,0 +1,133 @@
+package config
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+)
+
+// jsonSchemaEnums lists the accepted values of string fields, by struct and yaml key.
+// "" is listed where an empty value means the default, as LoadConfig accepts it.
+var jsonSchemaEnums = map[reflect.Type]map[string][]string{
+	reflect.TypeOf(Config{}): {
+		"dialect":        {"", "semver", "pep440", "maven"},
+		"zero_major":     {"", "standard", "shift"},
+		"unmatched_bump": {"", "major", "minor", "patch", "none"},
+	},
+	reflect.TypeOf(PathConfig{}): {
+		"dialect": {"", "semver", "pep440", "maven"},
+	},
+	reflect.TypeOf(CommitRule{}): {
+		"bump": {"major", "minor", "patch", "none"},
+	},
+	reflect.TypeOf(ComponentConfig{}): {
+		"reset": {"", "higher", "never"},
+	},
+	reflect.TypeOf(GitConfig{}): {
+		"backend": {"", "exec", "go-git"},
+		"shallow": {"", "fail", "fetch", "ignore"},
+	},
+	reflect.TypeOf(SigningConfig{}): {
+		"format": {"", "gpg", "ssh"},
+	},
+	reflect.TypeOf(TagConfig{}): {
+		"type": {"", "annotated", "lightweight"},
+	},
+}
+
+// jsonSchemaBumps lists the fields naming a bump level, by struct. Besides the levels in
+// jsonSchemaEnums they accept the name of any component declared in components.
+var jsonSchemaBumps = map[reflect.Type]string{
+	reflect.TypeOf(Config{}):     "unmatched_bump",
+	reflect.TypeOf(CommitRule{}): "bump",
+}
+
+// durationPattern matches the durations accepted by time.ParseDuration, such as "30s" or "1m30s"
+const durationPattern = `^(0|([0-9]+(\.[0-9]*)?|\.[0-9]+)(ns|us|µs|ms|s|m|h))+$`
+
+// JSONSchema returns a JSON Schema describing the configuration file, for editor
+// completion and validation in CI. It is generated from Config, so it always matches
+// the keys and types LoadConfig accepts.
+func JSONSchema() ([]byte, error) {
+	g := &jsonSchemaGenerator{defs: make(map[string]interface{})}
+	schema := g.object(reflect.TypeOf(Config{}))
+	schema["$schema"] = "https://json-schema.org/draft/2020-12/schema"
+	schema["title"] = "bumpit configuration"
+	schema["$defs"] = g.defs
+	return json.MarshalIndent(schema, "", "  ")
+}
+
+// jsonSchemaGenerator collects the definitions of the struct types used more than at the top level
+type jsonSchemaGenerator struct {
+	defs map[string]interface{}
+}
+
+// schema returns the schema of a value decoded into t
+func (g *jsonSchemaGenerator) schema(t reflect.Type) map[string]interface{} {
+	switch t {
+	case commandType:
+		// Commands are written as a command line, a list of arguments or a map
+		return map[string]interface{}{"anyOf": []interface{}{
+			map[string]interface{}{"type": "string"},
+			map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "string"}},
+			g.ref(t),
+		}}
+	case durationType:
+		return map[string]interface{}{"type": "string", "pattern": durationPattern}
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		return g.ref(t)
+	case reflect.Slice:
+		list := map[string]interface{}{"type": "array", "items": g.schema(t.Elem())}
+		// A single string is decoded as a comma separated list, or as one command line in a list of commands
+		if t.Elem().Kind() == reflect.String || t.Elem() == commandType {
+			return map[string]interface{}{"anyOf": []interface{}{list, map[string]interface{}{"type": "string"}}}
+		}
+		return list
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int:
+		return map[string]interface{}{"type": "integer"}
+	}
+	return map[string]interface{}{"type": "string"}
+}
+
+// ref returns a reference to the definition of a struct type, adding the definition on first use
+func (g *jsonSchemaGenerator) ref(t reflect.Type) map[string]interface{} {
+	if _, ok := g.defs[t.Name()]; !ok {
+		// Reserve the name first, so recursive types do not loop
+		g.defs[t.Name()] = nil
+		g.defs[t.Name()] = g.object(t)
+	}
+	return map[string]interface{}{"$ref": "#/$defs/" + t.Name()}
+}
+
+// object returns the schema of a struct, which only allows the keys of its fields
+func (g *jsonSchemaGenerator) object(t reflect.Type) map[string]interface{} {
+	properties := make(map[string]interface{})
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name := strings.Split(field.Tag.Get("yaml"), ",")[0]
+		if name == "" || name == "-" {
+			continue
+		}
+		property := g.schema(field.Type)
+		if enum, ok := jsonSchemaEnums[t][name]; ok {
+			property["enum"] = enum
+			if jsonSchemaBumps[t] == name {
+				property = map[string]interface{}{"anyOf": []interface{}{
+					property,
+					map[string]interface{}{"type": "string", "pattern": componentName.String()},
+				}}
+			}
+		}
+		properties[name] = property
+	}
+	return map[string]interface{}{
+		"type":                 "object",
+		"properties":           properties,
+		"additionalProperties": false,
+	}
+}
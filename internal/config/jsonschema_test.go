@@ -0,0 +1,199 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+var updateSchema = flag.Bool("update", false, "regenerate bumpit.schema.json")
+
+func TestJSONSchema(t *testing.T) {
+	data, err := JSONSchema()
+	if err != nil {
+		t.Fatalf("JSONSchema() error = %v", err)
+	}
+
+	var schema struct {
+		Properties           map[string]json.RawMessage `json:"properties"`
+		AdditionalProperties bool                       `json:"additionalProperties"`
+		Defs                 map[string]struct {
+			Properties map[string]struct {
+				Type string   `json:"type"`
+				Enum []string `json:"enum"`
+			} `json:"properties"`
+		} `json:"$defs"`
+	}
+	if err := json.Unmarshal(data, &schema); err != nil {
+		t.Fatalf("JSONSchema() is not valid JSON: %v", err)
+	}
+
+	if schema.AdditionalProperties {
+		t.Error("JSONSchema() allows unknown top-level keys")
+	}
+	for key := range yamlFields(reflect.TypeOf(Config{})) {
+		if _, ok := schema.Properties[key]; !ok {
+			t.Errorf("JSONSchema() is missing the key %q", key)
+		}
+	}
+	for _, name := range []string{"PathConfig", "CommitTypes", "GitConfig", "OutputConfig", "CommandConfig"} {
+		if _, ok := schema.Defs[name]; !ok {
+			t.Errorf("JSONSchema() is missing the definition of %s", name)
+		}
+	}
+	if got := schema.Defs["GitConfig"].Properties["fetch_depth"].Type; got != "integer" {
+		t.Errorf("git.fetch_depth type = %q, want integer", got)
+	}
+	if got := schema.Defs["PathConfig"].Properties["dialect"].Enum; !reflect.DeepEqual(got, []string{"", "semver", "pep440", "maven"}) {
+		t.Errorf("paths[].dialect enum = %q", got)
+	}
+
+	duration := regexp.MustCompile(durationPattern)
+	for _, value := range []string{"30s", "1m30s", "1.5h", "0"} {
+		if !duration.MatchString(value) {
+			t.Errorf("duration pattern does not match %q", value)
+		}
+	}
+	for _, value := range []string{"30", "forever", "-1s"} {
+		if duration.MatchString(value) {
+			t.Errorf("duration pattern matches %q", value)
+		}
+	}
+}
+
+// TestJSONSchemaFile checks the published schema matches the configuration.
+// Run go test ./internal/config -run TestJSONSchemaFile -update after changing Config.
+func TestJSONSchemaFile(t *testing.T) {
+	data, err := JSONSchema()
+	if err != nil {
+		t.Fatalf("JSONSchema() error = %v", err)
+	}
+	data = append(data, '\n')
+
+	file := filepath.Join("..", "..", "bumpit.schema.json")
+	if *updateSchema {
+		if err := os.WriteFile(file, data, 0644); err != nil {
+			t.Fatalf("Failed to write schema: %v", err)
+		}
+	}
+	published, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatalf("Failed to read schema: %v", err)
+	}
+	if !bytes.Equal(published, data) {
+		t.Error("bumpit.schema.json is out of date, run go test ./internal/config -run TestJSONSchemaFile -update")
+	}
+}
+
+func TestJSONSchemaAcceptsConfigs(t *testing.T) {
+	data, err := JSONSchema()
+	if err != nil {
+		t.Fatalf("JSONSchema() error = %v", err)
+	}
+	var schema map[string]interface{}
+	if err := json.Unmarshal(data, &schema); err != nil {
+		t.Fatalf("JSONSchema() is not valid JSON: %v", err)
+	}
+
+	files := []string{
+		filepath.Join("..", "..", "default.config.yaml"),
+		filepath.Join("..", "..", "examples", "config-examples", "monorepo.yaml"),
+	}
+	for _, file := range files {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatalf("Failed to read %s: %v", file, err)
+		}
+		var config interface{}
+		if err := yaml.Unmarshal(content, &config); err != nil {
+			t.Fatalf("Failed to parse %s: %v", file, err)
+		}
+		for _, problem := range validateJSONSchema(schema, schema, config, "") {
+			t.Errorf("%s: %s", file, problem)
+		}
+	}
+
+	var invalid interface{}
+	if err := yaml.Unmarshal([]byte("git:\n  backend: libgit\ncommit_types:\n  rules:\n    - bump: huge\n"), &invalid); err != nil {
+		t.Fatal(err)
+	}
+	if problems := validateJSONSchema(schema, schema, invalid, ""); len(problems) != 1 {
+		t.Errorf("JSONSchema() problems = %q, want only the backend", problems)
+	}
+}
+
+// validateJSONSchema checks a value against the keywords JSONSchema generates
+func validateJSONSchema(root, schema map[string]interface{}, value interface{}, key string) []string {
+	if ref, ok := schema["$ref"].(string); ok {
+		defs := root["$defs"].(map[string]interface{})
+		return validateJSONSchema(root, defs[filepath.Base(ref)].(map[string]interface{}), value, key)
+	}
+	if anyOf, ok := schema["anyOf"].([]interface{}); ok {
+		for _, option := range anyOf {
+			if len(validateJSONSchema(root, option.(map[string]interface{}), value, key)) == 0 {
+				return nil
+			}
+		}
+		return []string{key + ": matches none of the allowed forms"}
+	}
+
+	var problems []string
+	switch schema["type"] {
+	case "object":
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return []string{key + ": must be an object"}
+		}
+		properties := schema["properties"].(map[string]interface{})
+		for name, item := range object {
+			property, ok := properties[name]
+			if !ok {
+				problems = append(problems, joinKey(key, name)+": unknown key")
+				continue
+			}
+			problems = append(problems, validateJSONSchema(root, property.(map[string]interface{}), item, joinKey(key, name))...)
+		}
+	case "array":
+		items, ok := value.([]interface{})
+		if !ok {
+			return []string{key + ": must be an array"}
+		}
+		for i, item := range items {
+			problems = append(problems, validateJSONSchema(root, schema["items"].(map[string]interface{}), item, fmt.Sprintf("%s[%d]", key, i))...)
+		}
+	case "string":
+		s, ok := value.(string)
+		if !ok {
+			return []string{key + ": must be a string"}
+		}
+		if pattern, ok := schema["pattern"].(string); ok && !regexp.MustCompile(pattern).MatchString(s) {
+			problems = append(problems, key+": does not match "+pattern)
+		}
+		if enum, ok := schema["enum"].([]interface{}); ok {
+			found := false
+			for _, allowed := range enum {
+				found = found || allowed == s
+			}
+			if !found {
+				problems = append(problems, fmt.Sprintf("%s: %q is not one of %v", key, s, enum))
+			}
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			problems = append(problems, key+": must be a boolean")
+		}
+	case "integer":
+		if _, ok := value.(int); !ok {
+			problems = append(problems, key+": must be an integer")
+		}
+	}
+	return problems
+}
@@ -0,0 +1,383 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Problem is a single problem found in a configuration file
+type Problem struct {
+	File   string
+	Line   int
+	Column int
+	// Key is the path of the offending key, such as "paths[1].tag_pattern"
+	Key     string
+	Message string
+}
+
+func (p Problem) String() string {
+	location := p.File
+	if p.Line > 0 {
+		location += fmt.Sprintf(":%d:%d", p.Line, p.Column)
+	}
+	if p.Key == "" {
+		return location + ": " + p.Message
+	}
+	return fmt.Sprintf("%s: %s: %s", location, p.Key, p.Message)
+}
+
+// SchemaError lists every problem found in the configuration files
+type SchemaError struct {
+	Problems []Problem
+}
+
+func (e *SchemaError) Error() string {
+	lines := make([]string, len(e.Problems))
+	for i, problem := range e.Problems {
+		lines[i] = problem.String()
+	}
+	return "invalid configuration:\n  " + strings.Join(lines, "\n  ")
+}
+
+// ValidateFile checks a configuration file against the structure of Config and returns
+// a *SchemaError listing every problem with its line and key: unknown or duplicate keys,
+// values of the wrong type, invalid glob patterns, duplicate paths and tag patterns of
+// different paths that match the same tags.
+func ValidateFile(file string) error {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return fmt.Errorf("failed to read config file: %v", err)
+	}
+	return validateYAML(file, data)
+}
+
+// fileProblems returns the problems of a configuration file found by ValidateFile
+func fileProblems(file string) []Problem {
+	err := ValidateFile(file)
+	if schemaErr, ok := err.(*SchemaError); ok {
+		return schemaErr.Problems
+	}
+	if err != nil {
+		return []Problem{{File: file, Message: err.Error()}}
+	}
+	return nil
+}
+
+// validateYAML checks the content of a configuration file, see ValidateFile
+func validateYAML(file string, data []byte) error {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return &SchemaError{Problems: []Problem{{File: file, Message: err.Error()}}}
+	}
+	if len(doc.Content) == 0 {
+		return nil
+	}
+
+	s := &schemaChecker{file: file}
+	root := doc.Content[0]
+	s.check(root, reflect.TypeOf(Config{}), "")
+	if root.Kind == yaml.MappingNode {
+		s.checkGlobs(root)
+		s.checkPaths(root)
+	}
+	if len(s.problems) > 0 {
+		return &SchemaError{Problems: s.problems}
+	}
+	return nil
+}
+
+var (
+	commandType  = reflect.TypeOf(CommandConfig{})
+	durationType = reflect.TypeOf(time.Duration(0))
+)
+
+// schemaChecker collects the problems of a configuration file
+type schemaChecker struct {
+	file     string
+	problems []Problem
+}
+
+func (s *schemaChecker) add(node *yaml.Node, key, format string, args ...interface{}) {
+	s.problems = append(s.problems, Problem{
+		File:    s.file,
+		Line:    node.Line,
+		Column:  node.Column,
+		Key:     key,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+// check validates a node against the Go type it is decoded into
+func (s *schemaChecker) check(node *yaml.Node, t reflect.Type, key string) {
+	if node.Kind == yaml.AliasNode {
+		node = node.Alias
+	}
+	if node.Kind == yaml.ScalarNode && node.Tag == "!!null" {
+		return
+	}
+
+	switch t {
+	case commandType:
+		// Commands are written as a command line, a list of arguments or a map
+		switch node.Kind {
+		case yaml.ScalarNode:
+			return
+		case yaml.SequenceNode:
+			s.check(node, reflect.TypeOf([]string{}), key)
+			return
+		}
+	case durationType:
+		if node.Kind != yaml.ScalarNode {
+			s.add(node, key, "must be a duration such as \"30s\"")
+		} else if _, err := time.ParseDuration(node.Value); err != nil {
+			s.add(node, key, "must be a duration such as \"30s\", got %q", node.Value)
+		}
+		return
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		if node.Kind != yaml.MappingNode {
+			s.add(node, key, "must be a mapping")
+			return
+		}
+		fields := yamlFields(t)
+		seen := make(map[string]bool)
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			name, value := node.Content[i], node.Content[i+1]
+			child := joinKey(key, name.Value)
+			if seen[name.Value] {
+				s.add(name, child, "duplicate key")
+				continue
+			}
+			seen[name.Value] = true
+
+			field, ok := fields[name.Value]
+			if !ok {
+				if suggestion := closestKey(name.Value, fields); suggestion != "" {
+					s.add(name, child, "unknown key, did you mean %q?", suggestion)
+				} else {
+					s.add(name, child, "unknown key")
+				}
+				continue
+			}
+			s.check(value, field, child)
+		}
+	case reflect.Slice:
+		// A single string is decoded as a comma separated list, or as one command line in a list of commands
+		if node.Kind == yaml.ScalarNode && (t.Elem().Kind() == reflect.String || t.Elem() == commandType) {
+			return
+		}
+		if node.Kind != yaml.SequenceNode {
+			s.add(node, key, "must be a list")
+			return
+		}
+		for i, item := range node.Content {
+			s.check(item, t.Elem(), fmt.Sprintf("%s[%d]", key, i))
+		}
+	case reflect.String:
+		if node.Kind != yaml.ScalarNode {
+			s.add(node, key, "must be a string")
+		}
+	case reflect.Bool:
+		if _, err := strconv.ParseBool(node.Value); node.Kind != yaml.ScalarNode || err != nil {
+			s.add(node, key, "must be true or false")
+		}
+	case reflect.Int:
+		if _, err := strconv.Atoi(node.Value); node.Kind != yaml.ScalarNode || err != nil {
+			s.add(node, key, "must be an integer")
+		}
+	}
+}
+
+// yamlFields maps the yaml keys of a struct to the types of their fields
+func yamlFields(t reflect.Type) map[string]reflect.Type {
+	fields := make(map[string]reflect.Type, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name := strings.Split(field.Tag.Get("yaml"), ",")[0]
+		if name != "" && name != "-" {
+			fields[name] = field.Type
+		}
+	}
+	return fields
+}
+
+// closestKey suggests the known key closest to a misspelled one, or "" when none is close
+func closestKey(key string, fields map[string]reflect.Type) string {
+	best, bestDistance := "", 3
+	for name := range fields {
+		if d := editDistance(key, name); d < bestDistance || (d == bestDistance && best != "" && name < best) {
+			best, bestDistance = name, d
+		}
+	}
+	return best
+}
+
+// editDistance returns the Levenshtein distance between two strings
+func editDistance(a, b string) int {
+	previous := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current := make([]int, len(b)+1)
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous = current
+	}
+	return previous[len(b)]
+}
+
+func joinKey(parent, key string) string {
+	if parent == "" {
+		return key
+	}
+	return parent + "." + key
+}
+
+// keyedNode is a node found by lookup together with its key path
+type keyedNode struct {
+	node *yaml.Node
+	key  string
+}
+
+// lookup returns the scalar values found by following the keys from node,
+// where "[]" descends into every item of a list
+func lookup(node *yaml.Node, key string, keys ...string) []keyedNode {
+	if node.Kind == yaml.AliasNode {
+		node = node.Alias
+	}
+	if len(keys) == 0 {
+		if node.Kind != yaml.ScalarNode || node.Tag == "!!null" {
+			return nil
+		}
+		return []keyedNode{{node, key}}
+	}
+
+	var found []keyedNode
+	switch {
+	case keys[0] == "[]" && node.Kind == yaml.SequenceNode:
+		for i, item := range node.Content {
+			found = append(found, lookup(item, fmt.Sprintf("%s[%d]", key, i), keys[1:]...)...)
+		}
+	case node.Kind == yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if node.Content[i].Value == keys[0] {
+				found = append(found, lookup(node.Content[i+1], joinKey(key, keys[0]), keys[1:]...)...)
+				break
+			}
+		}
+	}
+	return found
+}
+
+// checkGlobs checks the glob patterns in the configuration can be matched
+func (s *schemaChecker) checkGlobs(root *yaml.Node) {
+	globs := [][]string{
+		{"git", "tag_pattern"},
+		{"paths", "[]", "tag_pattern"},
+		{"ignore", "paths", "[]"},
+		{"commit_types", "rules", "[]", "scope"},
+		{"paths", "[]", "commit_types", "rules", "[]", "scope"},
+	}
+	for _, keys := range globs {
+		for _, value := range lookup(root, "", keys...) {
+			if _, err := filepath.Match(value.node.Value, ""); err != nil {
+				s.add(value.node, value.key, "invalid glob pattern %q", value.node.Value)
+			}
+		}
+	}
+	for _, value := range lookup(root, "", "preflight", "branches", "[]") {
+		if _, err := path.Match(value.node.Value, ""); err != nil {
+			s.add(value.node, value.key, "invalid glob pattern %q", value.node.Value)
+		}
+	}
+}
+
+// checkPaths reports paths configured twice and tag patterns that match the tags of another path
+func (s *schemaChecker) checkPaths(root *yaml.Node) {
+	seen := make(map[string]string)
+	for _, value := range lookup(root, "", "paths", "[]", "path") {
+		cleaned := filepath.Clean(value.node.Value)
+		if first, ok := seen[cleaned]; ok {
+			s.add(value.node, value.key, "path %q is already configured by %s", value.node.Value, first)
+			continue
+		}
+		seen[cleaned] = value.key
+	}
+
+	// An empty tag pattern means the default, so only patterns that are set are compared
+	var patterns []keyedNode
+	for _, value := range append(lookup(root, "", "git", "tag_pattern"), lookup(root, "", "paths", "[]", "tag_pattern")...) {
+		if value.node.Value != "" {
+			patterns = append(patterns, value)
+		}
+	}
+	for j, b := range patterns {
+		for _, a := range patterns[:j] {
+			if globsOverlap(a.node.Value, b.node.Value) {
+				s.add(b.node, b.key, "tag pattern %q overlaps with %q of %s", b.node.Value, a.node.Value, a.key)
+				break
+			}
+		}
+	}
+}
+
+// globsOverlap reports whether two tag patterns can match the same tag. It checks whether
+// either pattern matches a tag made from the other one, which catches the usual overlaps
+// such as "v*" and "v*-sdk" while keeping "v*" and "sdk/v*" apart.
+func globsOverlap(a, b string) bool {
+	if matched, err := filepath.Match(a, globSample(b)); err == nil && matched {
+		return true
+	}
+	matched, err := filepath.Match(b, globSample(a))
+	return err == nil && matched
+}
+
+// globSample returns a name matched by the glob pattern, replacing wildcards by "0"
+// and character classes by their first character
+func globSample(pattern string) string {
+	var b strings.Builder
+	for i := 0; i < len(pattern); i++ {
+		switch c := pattern[i]; c {
+		case '*', '?':
+			b.WriteByte('0')
+		case '\\':
+			if i+1 < len(pattern) {
+				i++
+				b.WriteByte(pattern[i])
+			}
+		case '[':
+			end := strings.IndexByte(pattern[i:], ']')
+			if end < 0 {
+				b.WriteByte(c)
+				continue
+			}
+			class := pattern[i+1 : i+end]
+			if class != "" && class[0] != '^' && class[0] != '!' {
+				b.WriteByte(class[0])
+			} else {
+				b.WriteByte('0')
+			}
+			i += end
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,149 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestValidateYAML(t *testing.T) {
+	tests := []struct {
+		name   string
+		config string
+		want   []string
+	}{
+		{
+			name: "valid",
+			config: `version_prefix: "v"
+default_command: ["echo", "${version}"]
+git:
+  tag_pattern: "v*"
+  fetch_depth: 10
+  timeouts:
+    push: 2m
+hooks:
+  post_tag: "make publish"
+ignore:
+  types: chore,docs
+paths:
+  - path: "core"
+    tag_pattern: "core/v*"
+    hooks:
+      pre_bump:
+        - run: "make test"
+          timeout: 1m
+`,
+		},
+		{
+			name:   "unknown keys",
+			config: "version_prefx: \"v\"\ngit:\n  backend: exec\n  auto_psh: true\n  frobnicate: 1\n",
+			want: []string{
+				`test.yaml:1:1: version_prefx: unknown key, did you mean "version_prefix"?`,
+				`test.yaml:4:3: git.auto_psh: unknown key, did you mean "auto_push"?`,
+				`test.yaml:5:3: git.frobnicate: unknown key`,
+			},
+		},
+		{
+			name:   "wrong types",
+			config: "paths: core\ngit:\n  auto_push: sometimes\n  fetch_depth: deep\n  timeouts:\n    push: forever\noutput: [debug]\nversion_format: {major: 1}\n",
+			want: []string{
+				"test.yaml:1:8: paths: must be a list",
+				"test.yaml:3:14: git.auto_push: must be true or false",
+				"test.yaml:4:16: git.fetch_depth: must be an integer",
+				`test.yaml:6:11: git.timeouts.push: must be a duration such as "30s", got "forever"`,
+				"test.yaml:7:9: output: must be a mapping",
+				"test.yaml:8:17: version_format: must be a string",
+			},
+		},
+		{
+			name:   "duplicate keys and paths",
+			config: "dialect: semver\ndialect: calver\npaths:\n  - path: core\n  - path: ./core/\n",
+			want: []string{
+				"test.yaml:2:1: dialect: duplicate key",
+				`test.yaml:5:11: paths[1].path: path "./core/" is already configured by paths[0].path`,
+			},
+		},
+		{
+			name:   "invalid globs",
+			config: "git:\n  tag_pattern: \"v[\"\nignore:\n  paths: [\"docs/[\"]\npreflight:\n  branches: [\"release/[\"]\n",
+			want: []string{
+				`test.yaml:2:16: git.tag_pattern: invalid glob pattern "v["`,
+				`test.yaml:4:11: ignore.paths[0]: invalid glob pattern "docs/["`,
+				`test.yaml:6:14: preflight.branches[0]: invalid glob pattern "release/["`,
+			},
+		},
+		{
+			name:   "overlapping tag patterns",
+			config: "git:\n  tag_pattern: \"v*\"\npaths:\n  - path: sdk\n    tag_pattern: \"v*-sdk\"\n  - path: core\n    tag_pattern: \"core/v*\"\n",
+			want: []string{
+				`test.yaml:5:18: paths[0].tag_pattern: tag pattern "v*-sdk" overlaps with "v*" of git.tag_pattern`,
+			},
+		},
+		{
+			name:   "empty tag patterns",
+			config: "git:\n  tag_pattern: \"\"\npaths:\n  - path: sdk\n    tag_pattern: \"\"\n  - path: core\n    tag_pattern: \"\"\n",
+		},
+		{
+			name:   "syntax error",
+			config: "git:\n  tag_pattern: [\n",
+			want:   []string{"test.yaml: yaml: line 2"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateYAML("test.yaml", []byte(tt.config))
+			if len(tt.want) == 0 {
+				if err != nil {
+					t.Fatalf("validateYAML() error = %v", err)
+				}
+				return
+			}
+
+			var schemaErr *SchemaError
+			if !errors.As(err, &schemaErr) {
+				t.Fatalf("validateYAML() error = %v, want a SchemaError", err)
+			}
+			if len(schemaErr.Problems) != len(tt.want) {
+				t.Fatalf("validateYAML() problems = %q, want %d", err, len(tt.want))
+			}
+			for i, want := range tt.want {
+				if got := schemaErr.Problems[i].String(); !strings.HasPrefix(got, want) {
+					t.Errorf("problem %d = %q, want %q", i, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestValidateDefaultConfig(t *testing.T) {
+	if err := ValidateFile(filepath.Join("..", "..", "default.config.yaml")); err != nil {
+		t.Errorf("ValidateFile() error = %v", err)
+	}
+}
+
+func TestLoadConfigSchema(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	config := "paths:\n  - core\n  - path: api\n    version_format: 3\n    verison_prefix: \"api/v\"\n"
+	if err := os.WriteFile(configPath, []byte(config), 0644); err != nil {
+		t.Fatalf("Failed to write config file: %v", err)
+	}
+
+	os.Setenv("BUMPIT_CONFIG", configPath)
+	defer os.Unsetenv("BUMPIT_CONFIG")
+
+	_, err := LoadConfig()
+	if err == nil {
+		t.Fatal("LoadConfig() error = nil, want the schema problems")
+	}
+	for _, want := range []string{
+		configPath + ":2:5: paths[0]: must be a mapping",
+		configPath + ":5:5: paths[1].verison_prefix: unknown key",
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("LoadConfig() error = %q, want it to contain %q", err, want)
+		}
+	}
+}
@@ -0,0 +1,264 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// SourceDefault is the source of values that no configuration file sets
+const SourceDefault = "default"
+
+// source is a configuration file read by LoadConfig
+type source struct {
+	name string
+	raw  map[string]interface{}
+}
+
+// newSource parses the content of a configuration file, which has already been validated
+func newSource(name string, data []byte) source {
+	var raw map[string]interface{}
+	_ = yaml.Unmarshal(data, &raw)
+	return source{name: name, raw: raw}
+}
+
+// readSource reads a configuration file found by LoadConfig, named by its base name
+// such as default.config.yaml or .bumpit.yaml
+func readSource(file string) source {
+	data, _ := os.ReadFile(file)
+	return newSource(filepath.Base(file), data)
+}
+
+// Setting is a resolved configuration value and where it came from
+type Setting struct {
+	// Key is the path of the value, such as "git.tag_pattern" or "paths[0].dialect"
+	Key string
+	// Value is the resolved value, formatted as in YAML
+	Value string
+	// Source is "default", the configuration file that sets the value, or for paths
+	// the root key the value is inherited from. Values expanding environment variables
+	// also name the variables.
+	Source string
+}
+
+// Settings returns every resolved value of the root configuration followed by the
+// values of each path, with their source. Path values left empty show the root value
+// they inherit, as used when that path is released.
+func (c *Config) Settings() []Setting {
+	var settings []Setting
+	root := reflect.ValueOf(*c)
+	for _, field := range structFields(root) {
+		if field.name == "paths" {
+			continue
+		}
+		flatten(field.value, field.name, func(key string, value reflect.Value) {
+			settings = append(settings, c.setting(key, value, c.rootSource(sourceKeys(key))))
+		})
+	}
+
+	for i, pathConfig := range c.Paths {
+		pathConfig = c.inherit(pathConfig)
+		prefix := fmt.Sprintf("paths[%d]", i)
+		flatten(reflect.ValueOf(pathConfig), "", func(key string, value reflect.Value) {
+			settings = append(settings, c.setting(joinKey(prefix, key), value, c.pathSource(i, key)))
+		})
+	}
+	return settings
+}
+
+// Show writes the resolved configuration of the root and each path, annotated with
+// the source of every value.
+func (c *Config) Show(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "# root")
+	section := ""
+	for _, setting := range c.Settings() {
+		key := setting.Key
+		if strings.HasPrefix(key, "paths[") {
+			end := strings.Index(key, "].") + 1
+			if key[:end] != section {
+				section = key[:end]
+				fmt.Fprintf(tw, "\n# %s\n", section)
+			}
+			key = key[end+1:]
+		}
+		fmt.Fprintf(tw, "%s: %s\t# %s\n", key, setting.Value, setting.Source)
+	}
+	return tw.Flush()
+}
+
+// setting formats a resolved value, expanding the environment variables in pre_release
+// and build_metadata like the version calculation does
+func (c *Config) setting(key string, value reflect.Value, source string) Setting {
+	if value.Kind() == reflect.String && (strings.HasSuffix(key, "pre_release") || strings.HasSuffix(key, "build_metadata")) {
+		var names []string
+		expanded := os.Expand(value.String(), func(name string) string {
+			names = append(names, name)
+			return os.Getenv(name)
+		})
+		if len(names) > 0 {
+			return Setting{Key: key, Value: strconv.Quote(expanded), Source: source + ", env " + strings.Join(names, ", ")}
+		}
+	}
+	return Setting{Key: key, Value: formatValue(value), Source: source}
+}
+
+// rootSource returns the last configuration file that sets a key, or SourceDefault
+func (c *Config) rootSource(keys []string) string {
+	for i := len(c.sources) - 1; i >= 0; i-- {
+		if value, ok := rawLookup(c.sources[i].raw, keys); ok && isSet(value) {
+			return c.sources[i].name
+		}
+	}
+	return SourceDefault
+}
+
+// pathSource returns the source of a key of the i-th path configuration
+func (c *Config) pathSource(i int, key string) string {
+	keys := sourceKeys(key)
+	for j := len(c.sources) - 1; j >= 0; j-- {
+		paths, ok := c.sources[j].raw["paths"].([]interface{})
+		if !ok {
+			continue
+		}
+		// The paths of a later file replace those of earlier files
+		if i < len(paths) {
+			if value, ok := rawLookup(paths[i], keys); ok && isSet(value) {
+				return c.sources[j].name
+			}
+		}
+		break
+	}
+
+	if keys[0] == "path" {
+		return SourceDefault
+	}
+	rootKeys := keys
+	if keys[0] == "tag_pattern" {
+		rootKeys = []string{"git", "tag_pattern"}
+	}
+	rootKey := strings.Join(rootKeys, ".")
+	return fmt.Sprintf("inherited from %s (%s)", rootKey, c.rootSource(rootKeys))
+}
+
+// sourceKeys returns the keys a configuration file sets a value with. Lists are set
+// as a whole, so "hooks.pre_tag[0].run" is set by "hooks.pre_tag".
+func sourceKeys(key string) []string {
+	if i := strings.IndexByte(key, '['); i >= 0 {
+		key = key[:i]
+	}
+	return strings.Split(key, ".")
+}
+
+// rawLookup follows the keys through the parsed YAML of a configuration file. A value
+// that is not a mapping sets everything below it, as when a command is written as a string.
+func rawLookup(node interface{}, keys []string) (interface{}, bool) {
+	for _, key := range keys {
+		m, ok := node.(map[string]interface{})
+		if !ok {
+			return node, true
+		}
+		if node, ok = m[key]; !ok {
+			return nil, false
+		}
+	}
+	return node, true
+}
+
+// isSet reports whether a raw value sets a key, empty values are replaced by defaults
+func isSet(value interface{}) bool {
+	return value != nil && value != ""
+}
+
+// structField is a field of a configuration struct with its yaml key
+type structField struct {
+	name  string
+	value reflect.Value
+}
+
+// structFields returns the fields of a configuration struct that have a yaml key
+func structFields(v reflect.Value) []structField {
+	var fields []structField
+	for i := 0; i < v.NumField(); i++ {
+		name := strings.Split(v.Type().Field(i).Tag.Get("yaml"), ",")[0]
+		if name != "" && name != "-" {
+			fields = append(fields, structField{name, v.Field(i)})
+		}
+	}
+	return fields
+}
+
+// flatten calls emit for every value below v, descending into structs and lists of structs.
+// Commands are emitted as a single value.
+func flatten(v reflect.Value, key string, emit func(key string, value reflect.Value)) {
+	switch {
+	case v.Kind() == reflect.Struct && v.Type() != commandType:
+		for _, field := range structFields(v) {
+			flatten(field.value, joinKey(key, field.name), emit)
+		}
+	case v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.Struct && v.Len() > 0:
+		for i := 0; i < v.Len(); i++ {
+			flatten(v.Index(i), fmt.Sprintf("%s[%d]", key, i), emit)
+		}
+	default:
+		emit(key, v)
+	}
+}
+
+// formatValue formats a configuration value as in YAML
+func formatValue(v reflect.Value) string {
+	switch v.Type() {
+	case durationType:
+		return time.Duration(v.Int()).String()
+	case commandType:
+		return formatCommand(v.Interface().(CommandConfig))
+	}
+	switch v.Kind() {
+	case reflect.String:
+		return strconv.Quote(v.String())
+	case reflect.Slice:
+		if v.Len() == 0 {
+			return "[]"
+		}
+		data, err := json.Marshal(v.Interface())
+		if err != nil {
+			return fmt.Sprint(v.Interface())
+		}
+		return string(data)
+	}
+	return fmt.Sprint(v.Interface())
+}
+
+// formatCommand formats a command in the shortest form it can be written in
+func formatCommand(c CommandConfig) string {
+	var fields []string
+	if c.Run != "" {
+		fields = append(fields, "run: "+strconv.Quote(c.Run))
+	}
+	if len(c.Args) > 0 {
+		fields = append(fields, "args: "+formatValue(reflect.ValueOf(c.Args)))
+	}
+	if len(fields) == 1 && !c.Shell && c.Dir == "" && c.Timeout == 0 {
+		// Written as a command line or a list of arguments
+		return strings.TrimPrefix(strings.TrimPrefix(fields[0], "run: "), "args: ")
+	}
+	if c.Shell {
+		fields = append(fields, "shell: true")
+	}
+	if c.Dir != "" {
+		fields = append(fields, "dir: "+strconv.Quote(c.Dir))
+	}
+	if c.Timeout != 0 {
+		fields = append(fields, "timeout: "+c.Timeout.String())
+	}
+	return "{" + strings.Join(fields, ", ") + "}"
+}
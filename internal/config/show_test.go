@@ -0,0 +1,122 @@
+package config
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSettings(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"default.config.yaml": "version_prefix: \"v\"\ngit:\n  tag_pattern: \"v*\"\n  remote: \"upstream\"\n",
+		".bumpit.yaml": `pre_release: "rc.${BUMPIT_TEST_RUN}"
+git:
+  remote: "origin"
+hooks:
+  pre_tag: "make test"
+paths:
+  - path: core
+    version_prefix: "core/v"
+    tag_pattern: "core/v*"
+  - path: web
+    version_prefix: ""
+`,
+	}
+	for name, data := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(data), 0644); err != nil {
+			t.Fatalf("Failed to write config file: %v", err)
+		}
+	}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+	os.Setenv("BUMPIT_TEST_RUN", "7")
+	defer os.Unsetenv("BUMPIT_TEST_RUN")
+
+	cfg, err := LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+
+	settings := make(map[string]Setting)
+	for _, setting := range cfg.Settings() {
+		settings[setting.Key] = setting
+	}
+
+	tests := []struct {
+		key    string
+		value  string
+		source string
+	}{
+		{"version_prefix", `"v"`, "default.config.yaml"},
+		{"version_format", `"{major}.{minor}.{patch}"`, "default"},
+		{"pre_release", `"rc.7"`, ".bumpit.yaml, env BUMPIT_TEST_RUN"},
+		{"git.remote", `"origin"`, ".bumpit.yaml"},
+		{"git.tag_pattern", `"v*"`, "default.config.yaml"},
+		{"git.fetch_depth", "50", "default"},
+		{"hooks.pre_tag[0]", `"make test"`, ".bumpit.yaml"},
+		{"paths[0].version_prefix", `"core/v"`, ".bumpit.yaml"},
+		{"paths[0].tag_pattern", `"core/v*"`, ".bumpit.yaml"},
+		{"paths[0].pre_release", `"rc.7"`, "inherited from pre_release (.bumpit.yaml), env BUMPIT_TEST_RUN"},
+		{"paths[0].hooks.pre_tag[0]", `"make test"`, "inherited from hooks.pre_tag (.bumpit.yaml)"},
+		{"paths[1].version_prefix", `"v"`, "inherited from version_prefix (default.config.yaml)"},
+		{"paths[1].tag_pattern", `"v*"`, "inherited from git.tag_pattern (default.config.yaml)"},
+		{"paths[1].default_command", "{}", "inherited from default_command (default)"},
+	}
+	for _, tt := range tests {
+		got, ok := settings[tt.key]
+		if !ok {
+			t.Errorf("Settings() is missing %s", tt.key)
+			continue
+		}
+		if got.Value != tt.value || got.Source != tt.source {
+			t.Errorf("Settings() %s = %s (%s), want %s (%s)", tt.key, got.Value, got.Source, tt.value, tt.source)
+		}
+	}
+
+	var out bytes.Buffer
+	if err := cfg.Show(&out); err != nil {
+		t.Fatalf("Show() error = %v", err)
+	}
+	for _, want := range []string{"# root\n", "\n# paths[1]\npath: \"web\"", "# inherited from git.tag_pattern (default.config.yaml)"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("Show() = %q, want it to contain %q", out.String(), want)
+		}
+	}
+}
+
+func TestGetPathConfigInherits(t *testing.T) {
+	cfg := &Config{
+		VersionPrefix: "v",
+		PreRelease:    "rc.1",
+		BuildMetadata: "build.5",
+		Git:           GitConfig{TagPattern: "v*"},
+		Paths:         []PathConfig{{Path: "core", PreRelease: "beta.1"}},
+	}
+
+	for _, path := range []string{"core", "web", ""} {
+		got := cfg.GetPathConfig(path)
+		forPath := cfg.ForPath(path)
+		if got.PreRelease != forPath.PreRelease || got.BuildMetadata != forPath.BuildMetadata || got.TagPattern != forPath.Git.TagPattern {
+			t.Errorf("GetPathConfig(%q) = %+v, ForPath() = %+v", path, got, forPath)
+		}
+		if got.BuildMetadata != "build.5" || got.TagPattern != "v*" {
+			t.Errorf("GetPathConfig(%q) = build metadata %q, tag pattern %q, want the root values", path, got.BuildMetadata, got.TagPattern)
+		}
+	}
+	if got := cfg.GetPathConfig("core").PreRelease; got != "beta.1" {
+		t.Errorf("GetPathConfig() pre-release = %q, want beta.1", got)
+	}
+	if got := cfg.GetPathConfig("web").PreRelease; got != "rc.1" {
+		t.Errorf("GetPathConfig() pre-release = %q, want rc.1", got)
+	}
+}
@@ -0,0 +1,37 @@
+package git
+
+import "github.com/crazywolf132/bumpit/internal/config"
+
+// NewFromConfig creates a git interface for the repository in workDir set up by the
+// git section of the configuration
+func NewFromConfig(cfg config.GitConfig, workDir string) Interface {
+	return NewWithOptions(cfg.TagPattern, workDir, OptionsFromConfig(cfg))
+}
+
+// OptionsFromConfig maps the git section of the configuration to Options
+func OptionsFromConfig(cfg config.GitConfig) Options {
+	return Options{
+		Backend:     cfg.Backend,
+		FirstParent: cfg.FirstParent,
+		Timeouts: Timeouts{
+			Default: cfg.Timeouts.Default,
+			Read:    cfg.Timeouts.Read,
+			Tag:     cfg.Timeouts.Tag,
+			Push:    cfg.Timeouts.Push,
+			Fetch:   cfg.Timeouts.Fetch,
+		},
+		Remote: cfg.Remote,
+		Push: PushOptions{
+			Atomic:     cfg.Push.Atomic,
+			FollowTags: cfg.Push.FollowTags,
+			Refspecs:   cfg.Push.Refspecs,
+		},
+		Signing: SigningOptions{
+			Format:         cfg.Signing.Format,
+			Key:            cfg.Signing.Key,
+			AllowedSigners: cfg.Signing.AllowedSigners,
+			Strict:         cfg.Signing.Strict,
+		},
+		Lightweight: cfg.Tag.Type == "lightweight",
+	}
+}
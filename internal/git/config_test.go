@@ -0,0 +1,139 @@
+package git
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/crazywolf132/bumpit/internal/config"
+)
+
+// loadConfig loads a .bumpit.yaml with the given content
+func loadConfig(t *testing.T, content string) *config.Config {
+	t.Helper()
+	file := filepath.Join(t.TempDir(), ".bumpit.yaml")
+	if err := os.WriteFile(file, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write config file: %v", err)
+	}
+	t.Setenv("BUMPIT_CONFIG", file)
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	return cfg
+}
+
+func TestNewFromConfig(t *testing.T) {
+	dir, cleanup := setupTestRepo(t)
+	defer cleanup()
+
+	cfg := loadConfig(t, "git:\n  tag_pattern: \"v*\"\n  backend: go-git\n")
+	impl := NewFromConfig(cfg.Git, dir)
+	g, ok := impl.(*goGit)
+	if !ok {
+		t.Fatalf("NewFromConfig() with backend go-git = %T, want the go-git implementation", impl)
+	}
+	if g.tagPattern != "v*" {
+		t.Errorf("NewFromConfig() tag pattern = %q, want v*", g.tagPattern)
+	}
+
+	cfg = loadConfig(t, "git:\n  tag_pattern: \"v*\"\n")
+	if impl := NewFromConfig(cfg.Git, dir); reflect.TypeOf(impl) != reflect.TypeOf(&git{}) {
+		t.Errorf("NewFromConfig() without a backend = %T, want the exec implementation", impl)
+	}
+}
+
+func TestNewFromConfigFirstParent(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, backend string) {
+		dir, cleanup := setupHistory(t)
+		defer cleanup()
+
+		for firstParent, want := range map[bool]int{false: 4, true: 3} {
+			cfg := loadConfig(t, fmt.Sprintf("git:\n  backend: %s\n  first_parent: %v\n", backend, firstParent))
+			got, err := NewFromConfig(cfg.Git, dir).CountCommitsSinceTag("v2.0.0", "")
+			if err != nil || got != want {
+				t.Errorf("CountCommitsSinceTag() with first_parent %v = %v, %v, want %v", firstParent, got, err, want)
+			}
+		}
+	})
+}
+
+func TestOptionsFromConfig(t *testing.T) {
+	cfg := loadConfig(t, `git:
+  timeouts:
+    default: 1m
+    read: 10s
+    push: 2m
+  remote: upstream
+  push:
+    atomic: true
+    follow_tags: true
+    refspecs: ["HEAD", "main:release"]
+  signing:
+    format: ssh
+    key: ~/.ssh/release.pub
+    allowed_signers: .github/allowed_signers
+    strict: true
+  tag:
+    type: annotated
+`)
+	got := OptionsFromConfig(cfg.Git)
+	if want := (Timeouts{Default: time.Minute, Read: 10 * time.Second, Push: 2 * time.Minute}); got.Timeouts != want {
+		t.Errorf("OptionsFromConfig() timeouts = %+v, want %+v", got.Timeouts, want)
+	}
+	if got.Remote != "upstream" {
+		t.Errorf("OptionsFromConfig() remote = %q, want upstream", got.Remote)
+	}
+	if want := (PushOptions{Atomic: true, FollowTags: true, Refspecs: []string{"HEAD", "main:release"}}); !reflect.DeepEqual(got.Push, want) {
+		t.Errorf("OptionsFromConfig() push = %+v, want %+v", got.Push, want)
+	}
+	if want := (SigningOptions{Format: "ssh", Key: "~/.ssh/release.pub", AllowedSigners: ".github/allowed_signers", Strict: true}); got.Signing != want {
+		t.Errorf("OptionsFromConfig() signing = %+v, want %+v", got.Signing, want)
+	}
+	if got.Lightweight {
+		t.Error("OptionsFromConfig() creates lightweight tags, want annotated")
+	}
+	if cfg := loadConfig(t, "git:\n  tag:\n    type: lightweight\n"); !OptionsFromConfig(cfg.Git).Lightweight {
+		t.Error("OptionsFromConfig() with tag type lightweight creates annotated tags")
+	}
+}
+
+func TestNewFromConfigTimeout(t *testing.T) {
+	dir, cleanup := setupTestRepo(t)
+	defer cleanup()
+
+	cfg := loadConfig(t, "git:\n  timeouts:\n    read: 1ns\n")
+	if _, err := NewFromConfig(cfg.Git, dir).GetLatestTag(""); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("GetLatestTag() with a 1ns read timeout error = %v, want context.DeadlineExceeded", err)
+	}
+}
+
+func TestNewFromConfigPush(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, backend string) {
+		dir, cleanup := setupTestRepo(t)
+		defer cleanup()
+
+		remote := t.TempDir()
+		runGit(t, remote, []string{"init", "-q", "--bare"})
+		runGit(t, dir, []string{"remote", "add", "upstream", remote})
+
+		cfg := loadConfig(t, fmt.Sprintf("git:\n  backend: %s\n  remote: upstream\n  push:\n    refspecs: [\"HEAD\"]\n", backend))
+		g := NewFromConfig(cfg.Git, dir)
+		branch, err := g.GetCurrentBranch()
+		if err != nil {
+			t.Fatalf("GetCurrentBranch() error = %v", err)
+		}
+		if err := g.PushTag("v2.0.0"); err != nil {
+			t.Fatalf("PushTag() error = %v", err)
+		}
+		refs := remoteRefs(t, remote)
+		if !refs["refs/tags/v2.0.0"] || !refs["refs/heads/"+branch] {
+			t.Errorf("PushTag() to the configured remote pushed %v, want v2.0.0 and %s", refs, branch)
+		}
+	})
+}
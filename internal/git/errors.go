@@ -0,0 +1,96 @@
+package git
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Errors returned by the git interface. Use errors.Is to check for them,
+// the returned errors wrap them with details about the failed operation.
+var (
+	// ErrNoTags means the repository has no tags at all
+	ErrNoTags = errors.New("no tags found")
+	// ErrNoMatchingTags means the repository has tags but none matches the tag pattern
+	ErrNoMatchingTags = errors.New("no matching tags found")
+	// ErrNotARepo means the working directory is not inside a git repository
+	ErrNotARepo = errors.New("not a git repository")
+	// ErrShallowClone means the operation needs history that a shallow clone does not have
+	ErrShallowClone = errors.New("repository is a shallow clone")
+	// ErrTagExists means the tag already exists locally or on the remote
+	ErrTagExists = errors.New("tag already exists")
+	// ErrNoUpstream means HEAD is detached or its branch does not track an upstream branch
+	ErrNoUpstream = errors.New("no upstream branch")
+	// ErrInvalidSignature means a tag is unsigned or its signature could not be verified
+	ErrInvalidSignature = errors.New("invalid tag signature")
+)
+
+// CommandError is returned when a git command fails. It keeps the command's
+// standard error output and wraps the matching sentinel error, if any.
+type CommandError struct {
+	// Args are the arguments git was run with
+	Args []string
+	// Stderr is the trimmed standard error output of the command
+	Stderr string
+	// Err is the error returned by running the command
+	Err error
+	// Kind is the sentinel error the output was classified as, or nil
+	Kind error
+}
+
+func (e *CommandError) Error() string {
+	command := "git"
+	if sub := subcommand(e.Args); sub != "" {
+		command += " " + sub
+	}
+	msg := fmt.Sprintf("%s: %v", command, e.Err)
+	if e.Stderr != "" {
+		msg += "\n" + e.Stderr
+	}
+	return msg
+}
+
+// Unwrap returns the classified sentinel error and the underlying error
+func (e *CommandError) Unwrap() []error {
+	if e.Kind == nil {
+		return []error{e.Err}
+	}
+	return []error{e.Kind, e.Err}
+}
+
+// subcommand returns the git subcommand in the arguments, skipping -c configuration overrides
+func subcommand(args []string) string {
+	for i := 0; i < len(args); i++ {
+		if args[i] == "-c" {
+			i++
+			continue
+		}
+		return args[i]
+	}
+	return ""
+}
+
+// tagExists matches git tag refusing to overwrite a tag
+var tagExists = regexp.MustCompile(`^fatal: tag '.+' already exists$`)
+
+// classifyStderr maps well known failures of the git command run with args to the
+// sentinel errors. Only the exact messages git prints for the subcommand are matched,
+// so unrelated output mentioning the same words is not misclassified.
+func classifyStderr(args []string, stderr string) error {
+	sub := subcommand(args)
+	for _, line := range strings.Split(stderr, "\n") {
+		line = strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(line, "fatal: not a git repository"):
+			return ErrNotARepo
+		case sub == "tag" && tagExists.MatchString(line):
+			return ErrTagExists
+		case sub == "push" && strings.HasPrefix(line, "! [rejected]") && strings.HasSuffix(line, "(already exists)"):
+			return ErrTagExists
+		case (sub == "push" || sub == "fetch") && (line == "fatal: shallow update not allowed" || strings.HasSuffix(line, "(shallow update not allowed)")):
+			return ErrShallowClone
+		}
+	}
+	return nil
+}
@@ -4,41 +4,192 @@ package git
 
 import (
 	"bytes"
+	"context"
+	"errors"
 	"fmt"
 	"os/exec"
 	"path/filepath"
+	"strconv"
 	"strings"
+	"time"
 )
 
 type git struct {
 	tagPattern string
 	workDir    string
+	opts       Options
 }
 
-// New creates a new git interface
+// Options configures optional behaviour of the git interface
+type Options struct {
+	// Backend selects the implementation: "exec" runs the git binary, "go-git" uses a native implementation
+	Backend string
+	// FirstParent only follows the first parent of merge commits when reading history
+	FirstParent bool
+	// Timeouts bounds how long each kind of operation may run
+	Timeouts Timeouts
+	// Remote is the remote tags are pushed to and history is fetched from, "origin" when empty
+	Remote string
+	// Push configures how tags are pushed
+	Push PushOptions
+	// Signing configures signed tags and their verification
+	Signing SigningOptions
+	// Lightweight creates tags without a tag object, the tag message is ignored
+	Lightweight bool
+}
+
+// SigningOptions configures how release tags are signed and verified
+type SigningOptions struct {
+	// Format is "gpg" or "ssh", empty creates unsigned tags
+	Format string
+	// Key is the GPG key id or the path of the SSH key, empty uses git's user.signingkey
+	Key string
+	// AllowedSigners is the allowed signers file used to verify SSH signatures
+	AllowedSigners string
+	// Strict refuses to use a latest release tag without a valid signature
+	Strict bool
+}
+
+// configArgs returns the git configuration overrides needed to sign and verify tags
+func (s SigningOptions) configArgs() []string {
+	var args []string
+	if s.Format != "" {
+		args = append(args, "-c", "gpg.format="+s.Format)
+	}
+	if s.AllowedSigners != "" {
+		args = append(args, "-c", "gpg.ssh.allowedSignersFile="+s.AllowedSigners)
+	}
+	return args
+}
+
+// PushOptions configures how tags are pushed to the remote
+type PushOptions struct {
+	// Atomic makes the remote accept every pushed ref or none of them
+	Atomic bool
+	// FollowTags also pushes annotated tags that point at pushed commits
+	FollowTags bool
+	// Refspecs are pushed together with the tags, e.g. "HEAD" to push the release commit
+	Refspecs []string
+}
+
+// remote returns the configured remote name
+func (o Options) remote() string {
+	if o.Remote == "" {
+		return "origin"
+	}
+	return o.Remote
+}
+
+// Operations that can be given their own timeout
+const (
+	opRead  = "read"
+	opTag   = "tag"
+	opPush  = "push"
+	opFetch = "fetch"
+	// opWrite covers other local changes, such as resetting HEAD, and uses the default timeout
+	opWrite = "write"
+)
+
+// Timeouts bounds how long git operations may run. A zero duration means no limit.
+type Timeouts struct {
+	// Default applies to operations without a specific timeout
+	Default time.Duration
+	// Read applies to reading tags, history and the working tree status
+	Read time.Duration
+	// Tag applies to creating and deleting tags
+	Tag time.Duration
+	// Push applies to pushing tags to the remote
+	Push time.Duration
+	// Fetch applies to fetching tags and history from the remote
+	Fetch time.Duration
+}
+
+// withTimeout bounds the context by the timeout configured for the operation
+func (t Timeouts) withTimeout(ctx context.Context, op string) (context.Context, context.CancelFunc) {
+	timeout := t.Default
+	switch {
+	case op == opRead && t.Read > 0:
+		timeout = t.Read
+	case op == opTag && t.Tag > 0:
+		timeout = t.Tag
+	case op == opPush && t.Push > 0:
+		timeout = t.Push
+	case op == opFetch && t.Fetch > 0:
+		timeout = t.Fetch
+	}
+	if timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, timeout)
+}
+
+// New creates a new git interface with the default options.
+// Use NewFromConfig to apply the git section of the configuration.
 func New(tagPattern, workDir string) Interface {
+	return NewWithOptions(tagPattern, workDir, Options{})
+}
+
+// NewWithOptions creates a new git interface with the given options
+func NewWithOptions(tagPattern, workDir string, opts Options) Interface {
+	if opts.Backend == "go-git" {
+		return newGoGit(tagPattern, workDir, opts)
+	}
 	return &git{
 		tagPattern: tagPattern,
 		workDir:    workDir,
+		opts:       opts,
 	}
 }
 
-// GetLatestTag returns the latest tag that matches the pattern
-func (g *git) GetLatestTag(pattern string) (string, error) {
-	cmd := exec.Command("git", "tag", "--sort=-v:refname")
+// waitDelay is how long a killed git process may keep its output open,
+// e.g. through a credential helper or remote helper it started
+const waitDelay = time.Second
+
+// run executes git with the arguments in the working directory and returns its stdout.
+// The process is killed when the context is done or the operation times out.
+// Failures are returned as a *CommandError holding the standard error output.
+func (g *git) run(ctx context.Context, op string, args ...string) (string, error) {
+	ctx, cancel := g.opts.Timeouts.withTimeout(ctx, op)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "git", args...)
 	cmd.Dir = g.workDir
+	cmd.WaitDelay = waitDelay
 
 	var stdout, stderr bytes.Buffer
 	cmd.Stdout = &stdout
 	cmd.Stderr = &stderr
 
 	if err := cmd.Run(); err != nil {
-		return "", fmt.Errorf("failed to get tags: %v\n%s", err, stderr.String())
+		cmdErr := &CommandError{
+			Args:   args,
+			Stderr: strings.TrimSpace(stderr.String()),
+			Err:    err,
+		}
+		if ctx.Err() != nil {
+			cmdErr.Err = ctx.Err()
+		}
+		cmdErr.Kind = classifyStderr(args, cmdErr.Stderr)
+		return stdout.String(), cmdErr
+	}
+	return stdout.String(), nil
+}
+
+// GetLatestTag returns the latest tag that matches the pattern
+func (g *git) GetLatestTag(pattern string) (string, error) {
+	return g.GetLatestTagContext(context.Background(), pattern)
+}
+
+// GetLatestTagContext returns the latest tag that matches the pattern
+func (g *git) GetLatestTagContext(ctx context.Context, pattern string) (string, error) {
+	stdout, err := g.run(ctx, opRead, "tag", "--sort=-v:refname")
+	if err != nil {
+		return "", fmt.Errorf("failed to get tags: %w", err)
 	}
 
-	tags := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+	tags := strings.Split(strings.TrimSpace(stdout), "\n")
 	if len(tags) == 0 || (len(tags) == 1 && tags[0] == "") {
-		return "", fmt.Errorf("no tags found")
+		return "", ErrNoTags
 	}
 
 	// If no pattern is provided, use the instance's pattern
@@ -53,124 +204,379 @@ func (g *git) GetLatestTag(pattern string) (string, error) {
 			return "", fmt.Errorf("invalid pattern: %v", err)
 		}
 		if matched {
+			if g.opts.Signing.Strict {
+				if err := g.VerifyTagContext(ctx, tag); err != nil {
+					return "", err
+				}
+			}
 			return tag, nil
 		}
 	}
 
-	return "", fmt.Errorf("no matching tags found")
+	return "", ErrNoMatchingTags
+}
+
+// GetTags returns the tags matching the pattern, see GetTagsContext
+func (g *git) GetTags(pattern string) ([]string, error) {
+	return g.GetTagsContext(context.Background(), pattern)
+}
+
+// GetTagsContext returns the tags matching the pattern, newest version first.
+// An empty pattern uses the tag pattern of the instance.
+func (g *git) GetTagsContext(ctx context.Context, pattern string) ([]string, error) {
+	return g.listTags(ctx, pattern)
+}
+
+// GetTagsAtHead returns the tags matching the pattern that point at HEAD, see GetTagsAtHeadContext
+func (g *git) GetTagsAtHead(pattern string) ([]string, error) {
+	return g.GetTagsAtHeadContext(context.Background(), pattern)
+}
+
+// GetTagsAtHeadContext returns the tags matching the pattern that point at HEAD,
+// newest version first. An empty pattern uses the tag pattern of the instance.
+func (g *git) GetTagsAtHeadContext(ctx context.Context, pattern string) ([]string, error) {
+	return g.listTags(ctx, pattern, "--points-at", "HEAD")
+}
+
+// listTags lists the tags matching the pattern, newest version first, filtered by the extra git tag arguments
+func (g *git) listTags(ctx context.Context, pattern string, args ...string) ([]string, error) {
+	stdout, err := g.run(ctx, opRead, append([]string{"tag", "--sort=-v:refname"}, args...)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tags: %w", err)
+	}
+	if pattern == "" {
+		pattern = g.tagPattern
+	}
+
+	var tags []string
+	for _, tag := range strings.Fields(stdout) {
+		matched, err := filepath.Match(pattern, tag)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pattern: %v", err)
+		}
+		if matched {
+			tags = append(tags, tag)
+		}
+	}
+	return tags, nil
 }
 
 // GetCommitsSinceTag returns all commits since the given tag
 func (g *git) GetCommitsSinceTag(tag string) ([]string, error) {
-	cmd := exec.Command("git", "log", "--format=%B", tag+"..HEAD")
-	cmd.Dir = g.workDir
+	return g.GetCommitsSinceTagContext(context.Background(), tag)
+}
 
-	var stdout, stderr bytes.Buffer
-	cmd.Stdout = &stdout
-	cmd.Stderr = &stderr
+// GetCommitsSinceTagContext returns all commits since the given tag
+func (g *git) GetCommitsSinceTagContext(ctx context.Context, tag string) ([]string, error) {
+	stdout, err := g.run(ctx, opRead, "log", "--format=%B", tag+"..HEAD")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get commits: %w", err)
+	}
 
-	if err := cmd.Run(); err != nil {
-		return nil, fmt.Errorf("failed to get commits: %v\n%s", err, stderr.String())
+	return splitCommitMessages(stdout), nil
+}
+
+// GetCommitsSinceTagForPath returns all commits since the given tag for the specified path
+func (g *git) GetCommitsSinceTagForPath(tag, path string) ([]string, error) {
+	return g.GetCommitsSinceTagForPathContext(context.Background(), tag, path)
+}
+
+// GetCommitsSinceTagForPathContext returns all commits since the given tag for the specified path
+func (g *git) GetCommitsSinceTagForPathContext(ctx context.Context, tag, path string) ([]string, error) {
+	stdout, err := g.run(ctx, opRead, "log", "--format=%B", tag+"..HEAD", "--", path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get commits: %w", err)
 	}
 
-	commits := strings.Split(strings.TrimSpace(stdout.String()), "\n\n")
+	return splitCommitMessages(stdout), nil
+}
+
+// splitCommitMessages splits the output of git log --format=%B into commit messages
+func splitCommitMessages(out string) []string {
+	commits := strings.Split(strings.TrimSpace(out), "\n\n")
 	var filtered []string
 	for _, commit := range commits {
 		if commit = strings.TrimSpace(commit); commit != "" {
 			filtered = append(filtered, commit)
 		}
 	}
-	return filtered, nil
+	return filtered
 }
 
-// GetCommitsSinceTagForPath returns all commits since the given tag for the specified path
-func (g *git) GetCommitsSinceTagForPath(tag, path string) ([]string, error) {
-	cmd := exec.Command("git", "log", "--format=%B", tag+"..HEAD", "--", path)
-	cmd.Dir = g.workDir
+// GetCommitDetailsSinceTag returns the hash, parents, author, message and changed files of every
+// commit since the given tag, newest first. An empty tag returns the whole history and
+// an empty path does not restrict the commits to a path.
+func (g *git) GetCommitDetailsSinceTag(tag, path string) ([]Commit, error) {
+	return g.GetCommitDetailsSinceTagContext(context.Background(), tag, path)
+}
 
-	var stdout, stderr bytes.Buffer
-	cmd.Stdout = &stdout
-	cmd.Stderr = &stderr
+// GetCommitDetailsSinceTagContext returns the details of every commit since the given tag, see GetCommitDetailsSinceTag
+func (g *git) GetCommitDetailsSinceTagContext(ctx context.Context, tag, path string) ([]Commit, error) {
+	revision := "HEAD"
+	if tag != "" {
+		revision = tag + "..HEAD"
+	}
+	args := []string{"log", "--name-only", "--format=%x1e%H%x1f%P%x1f%an <%ae>%x1f%B%x1f"}
+	if g.opts.FirstParent {
+		args = append(args, "--first-parent")
+	}
+	args = append(args, revision)
+	if path != "" {
+		args = append(args, "--", path)
+	}
 
-	if err := cmd.Run(); err != nil {
-		return nil, fmt.Errorf("failed to get commits: %v\n%s", err, stderr.String())
+	stdout, err := g.run(ctx, opRead, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get commits: %w", err)
 	}
 
-	commits := strings.Split(strings.TrimSpace(stdout.String()), "\n\n")
-	var filtered []string
-	for _, commit := range commits {
-		if commit = strings.TrimSpace(commit); commit != "" {
-			filtered = append(filtered, commit)
+	return parseCommitLog(stdout), nil
+}
+
+// parseCommitLog parses the output of the log format used by GetCommitDetailsSinceTag
+func parseCommitLog(out string) []Commit {
+	var commits []Commit
+	for _, record := range strings.Split(out, "\x1e") {
+		fields := strings.Split(record, "\x1f")
+		if len(fields) != 5 {
+			continue
+		}
+
+		commit := Commit{
+			Hash:    strings.TrimSpace(fields[0]),
+			Parents: strings.Fields(fields[1]),
+			Author:  fields[2],
+			Message: strings.TrimSpace(fields[3]),
+		}
+		for _, file := range strings.Split(fields[4], "\n") {
+			if file = strings.TrimSpace(file); file != "" {
+				commit.Files = append(commit.Files, file)
+			}
 		}
+		commits = append(commits, commit)
 	}
-	return filtered, nil
+	return commits
 }
 
 // GetFirstCommit returns the hash of the first commit
 func (g *git) GetFirstCommit() (string, error) {
-	cmd := exec.Command("git", "rev-list", "--max-parents=0", "HEAD")
-	cmd.Dir = g.workDir
+	return g.GetFirstCommitContext(context.Background())
+}
 
-	var stdout, stderr bytes.Buffer
-	cmd.Stdout = &stdout
-	cmd.Stderr = &stderr
+// GetFirstCommitContext returns the hash of the first commit
+func (g *git) GetFirstCommitContext(ctx context.Context) (string, error) {
+	stdout, err := g.run(ctx, opRead, "rev-list", "--max-parents=0", "HEAD")
+	if err != nil {
+		return "", fmt.Errorf("failed to get first commit: %w", err)
+	}
 
-	if err := cmd.Run(); err != nil {
-		return "", fmt.Errorf("failed to get first commit: %v\n%s", err, stderr.String())
+	return strings.TrimSpace(stdout), nil
+}
+
+// GetHeadCommit returns the full hash of the commit HEAD points to
+func (g *git) GetHeadCommit() (string, error) {
+	return g.GetHeadCommitContext(context.Background())
+}
+
+// GetHeadCommitContext returns the full hash of the commit HEAD points to
+func (g *git) GetHeadCommitContext(ctx context.Context) (string, error) {
+	stdout, err := g.run(ctx, opRead, "rev-parse", "HEAD")
+	if err != nil {
+		return "", fmt.Errorf("failed to get head commit: %w", err)
+	}
+
+	return strings.TrimSpace(stdout), nil
+}
+
+// CountCommitsSinceTag returns the number of commits since the given tag, optionally
+// restricted to a path. An empty tag counts the whole history.
+func (g *git) CountCommitsSinceTag(tag, path string) (int, error) {
+	return g.CountCommitsSinceTagContext(context.Background(), tag, path)
+}
+
+// CountCommitsSinceTagContext returns the number of commits since the given tag, see CountCommitsSinceTag
+func (g *git) CountCommitsSinceTagContext(ctx context.Context, tag, path string) (int, error) {
+	revision := "HEAD"
+	if tag != "" {
+		revision = tag + "..HEAD"
+	}
+	args := []string{"rev-list", "--count"}
+	if g.opts.FirstParent {
+		args = append(args, "--first-parent")
+	}
+	args = append(args, revision)
+	if path != "" {
+		args = append(args, "--", path)
 	}
 
-	return strings.TrimSpace(stdout.String()), nil
+	stdout, err := g.run(ctx, opRead, args...)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count commits: %w", err)
+	}
+
+	count, err := strconv.Atoi(strings.TrimSpace(stdout))
+	if err != nil {
+		return 0, fmt.Errorf("failed to count commits: %v", err)
+	}
+	return count, nil
 }
 
 // HasChanges returns true if there are uncommitted changes
 func (g *git) HasChanges() (bool, error) {
-	cmd := exec.Command("git", "status", "--porcelain")
-	cmd.Dir = g.workDir
-
-	var stdout, stderr bytes.Buffer
-	cmd.Stdout = &stdout
-	cmd.Stderr = &stderr
+	return g.HasChangesContext(context.Background())
+}
 
-	if err := cmd.Run(); err != nil {
-		return false, fmt.Errorf("failed to get status: %v\n%s", err, stderr.String())
+// HasChangesContext returns true if there are uncommitted changes
+func (g *git) HasChangesContext(ctx context.Context) (bool, error) {
+	stdout, err := g.run(ctx, opRead, "status", "--porcelain")
+	if err != nil {
+		return false, fmt.Errorf("failed to get status: %w", err)
 	}
 
-	return stdout.String() != "", nil
+	return stdout != "", nil
 }
 
 // CreateTag creates a new git tag
 func (g *git) CreateTag(tag string, message string) error {
-	cmd := exec.Command("git", "tag", "-a", tag, "-m", message)
-	cmd.Dir = g.workDir
-	return cmd.Run()
+	return g.CreateTagContext(context.Background(), tag, message)
+}
+
+// CreateTagContext creates a new git tag, signed when signing is configured
+func (g *git) CreateTagContext(ctx context.Context, tag string, message string) error {
+	if g.opts.Lightweight {
+		if _, err := g.run(ctx, opTag, "tag", tag); err != nil {
+			return fmt.Errorf("failed to create tag: %w", err)
+		}
+		return nil
+	}
+
+	args := append(g.opts.Signing.configArgs(), "tag")
+	switch {
+	case g.opts.Signing.Format == "":
+		args = append(args, "-a")
+	case g.opts.Signing.Key != "":
+		args = append(args, "-u", g.opts.Signing.Key)
+	default:
+		args = append(args, "-s")
+	}
+	// Keep lines starting with # such as the headings of release notes, which git strips by default
+	args = append(args, "--cleanup=whitespace", tag, "-m", message)
+
+	if _, err := g.run(ctx, opTag, args...); err != nil {
+		return fmt.Errorf("failed to create tag: %w", err)
+	}
+	return nil
+}
+
+// VerifyTag checks the signature of a tag, see VerifyTagContext
+func (g *git) VerifyTag(tag string) error {
+	return g.VerifyTagContext(context.Background(), tag)
+}
+
+// VerifyTagContext checks the signature of a tag and returns an error wrapping
+// ErrInvalidSignature when the tag is unsigned or its signature is not valid
+func (g *git) VerifyTagContext(ctx context.Context, tag string) error {
+	args := append(g.opts.Signing.configArgs(), "tag", "-v", tag)
+	if _, err := g.run(ctx, opRead, args...); err != nil {
+		if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+			return fmt.Errorf("failed to verify tag %s: %w", tag, err)
+		}
+		return fmt.Errorf("%w: %s: %w", ErrInvalidSignature, tag, err)
+	}
+	return nil
+}
+
+// DeleteTag deletes a local tag, see DeleteTagContext
+func (g *git) DeleteTag(tag string) error {
+	return g.DeleteTagContext(context.Background(), tag)
+}
+
+// DeleteTagContext deletes a local tag. Tags already pushed to the remote are left alone.
+func (g *git) DeleteTagContext(ctx context.Context, tag string) error {
+	if _, err := g.run(ctx, opTag, "tag", "-d", tag); err != nil {
+		return fmt.Errorf("failed to delete tag %s: %w", tag, err)
+	}
+	return nil
 }
 
 // PushTag pushes a tag to the remote repository
 func (g *git) PushTag(tag string) error {
-	cmd := exec.Command("git", "push", "origin", tag)
-	cmd.Dir = g.workDir
-	return cmd.Run()
+	return g.PushTagContext(context.Background(), tag)
+}
+
+// PushTagContext pushes a tag to the remote repository
+func (g *git) PushTagContext(ctx context.Context, tag string) error {
+	return g.PushTagsContext(ctx, []string{tag})
+}
+
+// PushTags pushes the tags and the configured refspecs to the remote in a single push
+func (g *git) PushTags(tags []string) error {
+	return g.PushTagsContext(context.Background(), tags)
+}
+
+// PushTagsContext pushes the tags and the configured refspecs to the remote in a single push
+func (g *git) PushTagsContext(ctx context.Context, tags []string) error {
+	args := []string{"push"}
+	if g.opts.Push.Atomic {
+		args = append(args, "--atomic")
+	}
+	if g.opts.Push.FollowTags {
+		args = append(args, "--follow-tags")
+	}
+	args = append(args, g.opts.remote())
+	for _, tag := range tags {
+		args = append(args, tagRefSpec(tag))
+	}
+	args = append(args, g.opts.Push.Refspecs...)
+
+	if _, err := g.run(ctx, opPush, args...); err != nil {
+		return fmt.Errorf("failed to push tag: %w", err)
+	}
+	return nil
+}
+
+// HasRemoteTag reports whether the tag exists on the remote, see HasRemoteTagContext
+func (g *git) HasRemoteTag(tag string) (bool, error) {
+	return g.HasRemoteTagContext(context.Background(), tag)
+}
+
+// HasRemoteTagContext reports whether the tag exists on the configured remote
+func (g *git) HasRemoteTagContext(ctx context.Context, tag string) (bool, error) {
+	stdout, err := g.run(ctx, opFetch, "ls-remote", "--tags", g.opts.remote(), "refs/tags/"+tag)
+	if err != nil {
+		return false, fmt.Errorf("failed to list remote tags: %w", err)
+	}
+	return strings.TrimSpace(stdout) != "", nil
+}
+
+// tagRefSpec returns the refspec that pushes a tag to the same name on the remote
+func tagRefSpec(tag string) string {
+	return "refs/tags/" + tag + ":refs/tags/" + tag
 }
 
 // GetCurrentBranch returns the name of the current branch
 func (g *git) GetCurrentBranch() (string, error) {
-	cmd := exec.Command("git", "rev-parse", "--abbrev-ref", "HEAD")
-	cmd.Dir = g.workDir
-
-	var stdout, stderr bytes.Buffer
-	cmd.Stdout = &stdout
-	cmd.Stderr = &stderr
+	return g.GetCurrentBranchContext(context.Background())
+}
 
-	if err := cmd.Run(); err != nil {
-		return "", fmt.Errorf("failed to get current branch: %v\n%s", err, stderr.String())
+// GetCurrentBranchContext returns the name of the current branch
+func (g *git) GetCurrentBranchContext(ctx context.Context) (string, error) {
+	stdout, err := g.run(ctx, opRead, "rev-parse", "--abbrev-ref", "HEAD")
+	if err != nil {
+		return "", fmt.Errorf("failed to get current branch: %w", err)
 	}
 
-	return strings.TrimSpace(stdout.String()), nil
+	return strings.TrimSpace(stdout), nil
 }
 
 // IsClean returns true if the repository has no uncommitted changes
 func (g *git) IsClean() (bool, error) {
-	hasChanges, err := g.HasChanges()
+	return g.IsCleanContext(context.Background())
+}
+
+// IsCleanContext returns true if the repository has no uncommitted changes
+func (g *git) IsCleanContext(ctx context.Context) (bool, error) {
+	hasChanges, err := g.HasChangesContext(ctx)
 	if err != nil {
 		return false, err
 	}
@@ -192,10 +598,117 @@ func matchPattern(pattern, str string) (bool, error) {
 
 // GetCurrentVersion returns the current version based on the latest tag
 func (g *git) GetCurrentVersion() (string, error) {
-	return g.GetLatestTag(g.tagPattern)
+	return g.GetCurrentVersionContext(context.Background())
+}
+
+// GetCurrentVersionContext returns the current version based on the latest tag
+func (g *git) GetCurrentVersionContext(ctx context.Context) (string, error) {
+	return g.GetLatestTagContext(ctx, g.tagPattern)
 }
 
 // GetCommitsSinceVersion returns all commits since the given version
 func (g *git) GetCommitsSinceVersion(version string) ([]string, error) {
-	return g.GetCommitsSinceTag(version)
+	return g.GetCommitsSinceVersionContext(context.Background(), version)
+}
+
+// GetCommitsSinceVersionContext returns all commits since the given version
+func (g *git) GetCommitsSinceVersionContext(ctx context.Context, version string) ([]string, error) {
+	return g.GetCommitsSinceTagContext(ctx, version)
+}
+
+// IsShallow reports whether the repository is a shallow clone
+func (g *git) IsShallow() (bool, error) {
+	return g.IsShallowContext(context.Background())
+}
+
+// IsShallowContext reports whether the repository is a shallow clone
+func (g *git) IsShallowContext(ctx context.Context) (bool, error) {
+	stdout, err := g.run(ctx, opRead, "rev-parse", "--is-shallow-repository")
+	if err != nil {
+		return false, fmt.Errorf("failed to check for shallow clone: %w", err)
+	}
+
+	return strings.TrimSpace(stdout) == "true", nil
+}
+
+// IsAncestor reports whether the tag is reachable from HEAD
+func (g *git) IsAncestor(tag string) (bool, error) {
+	return g.IsAncestorContext(context.Background(), tag)
+}
+
+// IsAncestorContext reports whether the tag is reachable from HEAD
+func (g *git) IsAncestorContext(ctx context.Context, tag string) (bool, error) {
+	_, err := g.run(ctx, opRead, "merge-base", "--is-ancestor", tag, "HEAD")
+	if err == nil {
+		return true, nil
+	}
+
+	// merge-base exits with 1 and no output when the tag is not an ancestor
+	var exitErr *exec.ExitError
+	var cmdErr *CommandError
+	if errors.As(err, &cmdErr) && cmdErr.Stderr == "" && errors.As(err, &exitErr) && exitErr.ExitCode() == 1 {
+		return false, nil
+	}
+	return false, fmt.Errorf("failed to check ancestry of %s: %w", tag, err)
+}
+
+// Deepen fetches all tags and depth more commits of history from the remote, see DeepenContext
+func (g *git) Deepen(depth int) error {
+	return g.DeepenContext(context.Background(), depth)
+}
+
+// DeepenContext fetches all tags and depth more commits of history from the remote.
+// A depth of zero or less fetches the complete history.
+func (g *git) DeepenContext(ctx context.Context, depth int) error {
+	args := []string{"fetch", "--tags", "--force", g.opts.remote()}
+	if depth > 0 {
+		args = append(args, "--deepen="+strconv.Itoa(depth))
+	} else {
+		args = append(args, "--unshallow")
+	}
+
+	if _, err := g.run(ctx, opFetch, args...); err != nil {
+		return fmt.Errorf("failed to fetch history: %w", err)
+	}
+	return nil
+}
+
+// BehindUpstream counts the commits of the upstream branch missing from HEAD, see BehindUpstreamContext
+func (g *git) BehindUpstream() (int, error) {
+	return g.BehindUpstreamContext(context.Background())
+}
+
+// BehindUpstreamContext counts the commits of the current branch's upstream that HEAD does
+// not contain, as last fetched. It returns an error wrapping ErrNoUpstream when HEAD is
+// detached or the branch does not track an upstream branch.
+func (g *git) BehindUpstreamContext(ctx context.Context) (int, error) {
+	stdout, err := g.run(ctx, opRead, "rev-list", "--count", "HEAD..@{upstream}")
+	if err != nil {
+		var cmdErr *CommandError
+		if errors.As(err, &cmdErr) && (strings.Contains(cmdErr.Stderr, "no upstream") ||
+			strings.Contains(cmdErr.Stderr, "does not point to a branch")) {
+			err = fmt.Errorf("%w: %w", ErrNoUpstream, err)
+		}
+		return 0, fmt.Errorf("failed to compare with upstream: %w", err)
+	}
+
+	count, err := strconv.Atoi(strings.TrimSpace(stdout))
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse commit count: %w", err)
+	}
+	return count, nil
+}
+
+// Reset moves HEAD and the index to the commit, see ResetContext
+func (g *git) Reset(commit string) error {
+	return g.ResetContext(context.Background(), commit)
+}
+
+// ResetContext moves HEAD and the index to the commit. The working tree is left as it is,
+// so resetting a release commit keeps its changes as uncommitted modifications.
+func (g *git) ResetContext(ctx context.Context, commit string) error {
+	if _, err := g.run(ctx, opWrite, "reset", "-q", commit); err != nil {
+		return fmt.Errorf("failed to reset to %s: %w", commit, err)
+	}
+	return nil
 }
@@ -1,12 +1,29 @@
 package git
 
 import (
+	"context"
+	"errors"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
+	"time"
 )
 
+// backends lists the git implementations every test runs against
+var backends = []string{"exec", "go-git"}
+
+// forEachBackend runs a test once for every git implementation
+func forEachBackend(t *testing.T, test func(t *testing.T, backend string)) {
+	for _, backend := range backends {
+		t.Run(backend, func(t *testing.T) {
+			test(t, backend)
+		})
+	}
+}
+
 func setupTestRepo(t *testing.T) (string, func()) {
 	t.Helper()
 
@@ -83,12 +100,14 @@ func setupTestRepo(t *testing.T) (string, func()) {
 }
 
 func TestGetLatestTag(t *testing.T) {
+	forEachBackend(t, testGetLatestTag)
+}
+
+func testGetLatestTag(t *testing.T, backend string) {
 	dir, cleanup := setupTestRepo(t)
 	defer cleanup()
 
-	g := &git{
-		workDir: dir,
-	}
+	g := NewWithOptions("", dir, Options{Backend: backend})
 
 	tests := []struct {
 		name        string
@@ -136,12 +155,14 @@ func TestGetLatestTag(t *testing.T) {
 }
 
 func TestGetCommitsSinceTag(t *testing.T) {
+	forEachBackend(t, testGetCommitsSinceTag)
+}
+
+func testGetCommitsSinceTag(t *testing.T, backend string) {
 	dir, cleanup := setupTestRepo(t)
 	defer cleanup()
 
-	g := &git{
-		workDir: dir,
-	}
+	g := NewWithOptions("", dir, Options{Backend: backend})
 
 	// Add a new commit
 	testFile := filepath.Join(dir, "test2.txt")
@@ -176,12 +197,14 @@ func TestGetCommitsSinceTag(t *testing.T) {
 }
 
 func TestGetCommitsSinceTagForPath(t *testing.T) {
+	forEachBackend(t, testGetCommitsSinceTagForPath)
+}
+
+func testGetCommitsSinceTagForPath(t *testing.T, backend string) {
 	dir, cleanup := setupTestRepo(t)
 	defer cleanup()
 
-	g := &git{
-		workDir: dir,
-	}
+	g := NewWithOptions("", dir, Options{Backend: backend})
 
 	// Create a subdirectory and add a commit
 	subDir := filepath.Join(dir, "packages", "core")
@@ -219,3 +242,565 @@ func TestGetCommitsSinceTagForPath(t *testing.T) {
 		t.Errorf("GetCommitsSinceTagForPath() = %v, want 'feat(core): new core feature'", commits[0])
 	}
 }
+
+func TestGetCommitDetailsSinceTag(t *testing.T) {
+	forEachBackend(t, testGetCommitDetailsSinceTag)
+}
+
+func testGetCommitDetailsSinceTag(t *testing.T, backend string) {
+	dir, cleanup := setupTestRepo(t)
+	defer cleanup()
+
+	g := NewWithOptions("", dir, Options{Backend: backend})
+
+	docsDir := filepath.Join(dir, "docs")
+	if err := os.MkdirAll(docsDir, 0755); err != nil {
+		t.Fatalf("Failed to create subdirectory: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(docsDir, "intro.md"), []byte("intro"), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	cmds := [][]string{
+		{"git", "add", "docs/intro.md"},
+		{"git", "commit", "-m", "docs: add intro", "-m", "Longer explanation.\n\nWith paragraphs."},
+	}
+	for _, c := range cmds {
+		cmd := exec.Command(c[0], c[1:]...)
+		cmd.Dir = dir
+		if err := cmd.Run(); err != nil {
+			t.Fatalf("Failed to run command %v: %v", c, err)
+		}
+	}
+
+	commits, err := g.GetCommitDetailsSinceTag("v2.0.0", "")
+	if err != nil {
+		t.Fatalf("GetCommitDetailsSinceTag() error = %v", err)
+	}
+	if len(commits) != 1 {
+		t.Fatalf("GetCommitDetailsSinceTag() = %v commits, want 1", len(commits))
+	}
+
+	got := commits[0]
+	if len(got.Hash) != 40 {
+		t.Errorf("GetCommitDetailsSinceTag() hash = %q, want a full commit hash", got.Hash)
+	}
+	if got.Author != "Test User <test@example.com>" {
+		t.Errorf("GetCommitDetailsSinceTag() author = %q, want 'Test User <test@example.com>'", got.Author)
+	}
+	if got.Message != "docs: add intro\n\nLonger explanation.\n\nWith paragraphs." {
+		t.Errorf("GetCommitDetailsSinceTag() message = %q", got.Message)
+	}
+	if len(got.Files) != 1 || got.Files[0] != "docs/intro.md" {
+		t.Errorf("GetCommitDetailsSinceTag() files = %v, want [docs/intro.md]", got.Files)
+	}
+
+	all, err := g.GetCommitDetailsSinceTag("", "")
+	if err != nil {
+		t.Fatalf("GetCommitDetailsSinceTag() error = %v", err)
+	}
+	if len(all) != 2 {
+		t.Errorf("GetCommitDetailsSinceTag() without tag = %v commits, want 2", len(all))
+	}
+}
+
+func TestGetCommitDetailsFirstParent(t *testing.T) {
+	forEachBackend(t, testGetCommitDetailsFirstParent)
+}
+
+func testGetCommitDetailsFirstParent(t *testing.T, backend string) {
+	dir, cleanup := setupTestRepo(t)
+	defer cleanup()
+
+	cmds := [][]string{
+		{"git", "checkout", "-q", "-b", "feature"},
+		{"git", "commit", "-q", "--allow-empty", "-m", "feat: branch work"},
+		{"git", "checkout", "-q", "-"},
+		{"git", "commit", "-q", "--allow-empty", "-m", "fix: mainline fix"},
+		{"git", "merge", "-q", "--no-ff", "--no-edit", "feature"},
+	}
+	for _, c := range cmds {
+		cmd := exec.Command(c[0], c[1:]...)
+		cmd.Dir = dir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("Failed to run command %v: %v\n%s", c, err, out)
+		}
+	}
+
+	all, err := NewWithOptions("", dir, Options{Backend: backend}).GetCommitDetailsSinceTag("v2.0.0", "")
+	if err != nil {
+		t.Fatalf("GetCommitDetailsSinceTag() error = %v", err)
+	}
+	if len(all) != 3 {
+		t.Fatalf("GetCommitDetailsSinceTag() = %v commits, want 3", len(all))
+	}
+	if !all[0].IsMerge() {
+		t.Errorf("GetCommitDetailsSinceTag() first commit should be the merge, got %q", all[0].Message)
+	}
+
+	firstParent, err := NewWithOptions("", dir, Options{Backend: backend, FirstParent: true}).GetCommitDetailsSinceTag("v2.0.0", "")
+	if err != nil {
+		t.Fatalf("GetCommitDetailsSinceTag() error = %v", err)
+	}
+	if len(firstParent) != 2 {
+		t.Errorf("GetCommitDetailsSinceTag() with first parent = %v commits, want 2", len(firstParent))
+	}
+}
+
+func TestCountCommitsSinceTag(t *testing.T) {
+	forEachBackend(t, testCountCommitsSinceTag)
+}
+
+func testCountCommitsSinceTag(t *testing.T, backend string) {
+	dir, cleanup := setupTestRepo(t)
+	defer cleanup()
+
+	g := NewWithOptions("", dir, Options{Backend: backend})
+
+	for _, msg := range []string{"fix: one", "fix: two"} {
+		cmd := exec.Command("git", "commit", "-q", "--allow-empty", "-m", msg)
+		cmd.Dir = dir
+		if err := cmd.Run(); err != nil {
+			t.Fatalf("Failed to create commit: %v", err)
+		}
+	}
+
+	count, err := g.CountCommitsSinceTag("v2.0.0", "")
+	if err != nil {
+		t.Fatalf("CountCommitsSinceTag() error = %v", err)
+	}
+	if count != 2 {
+		t.Errorf("CountCommitsSinceTag() = %v, want 2", count)
+	}
+
+	count, err = g.CountCommitsSinceTag("", "")
+	if err != nil {
+		t.Fatalf("CountCommitsSinceTag() error = %v", err)
+	}
+	if count != 3 {
+		t.Errorf("CountCommitsSinceTag() without tag = %v, want 3", count)
+	}
+
+	head, err := g.GetHeadCommit()
+	if err != nil {
+		t.Fatalf("GetHeadCommit() error = %v", err)
+	}
+	if len(head) != 40 {
+		t.Errorf("GetHeadCommit() = %q, want a full commit hash", head)
+	}
+}
+
+func TestContextCancellation(t *testing.T) {
+	forEachBackend(t, testContextCancellation)
+}
+
+func testContextCancellation(t *testing.T, backend string) {
+	dir, cleanup := setupTestRepo(t)
+	defer cleanup()
+
+	g := NewWithOptions("v*", dir, Options{Backend: backend})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := g.GetLatestTagContext(ctx, "v*"); err == nil || !strings.Contains(err.Error(), "context canceled") {
+		t.Errorf("GetLatestTagContext() error = %v, want context canceled", err)
+	}
+	if _, err := g.GetCommitDetailsSinceTagContext(ctx, "v1.0.0", ""); err == nil || !strings.Contains(err.Error(), "context canceled") {
+		t.Errorf("GetCommitDetailsSinceTagContext() error = %v, want context canceled", err)
+	}
+	if err := g.CreateTagContext(ctx, "v3.0.0", "Cancelled release"); err == nil {
+		t.Errorf("CreateTagContext() error = nil, want context canceled")
+	}
+
+	// Nothing may have been tagged by the cancelled call
+	if tag, err := g.GetLatestTag("v*"); err != nil || tag != "v2.0.0" {
+		t.Errorf("GetLatestTag() = %v, %v, want v2.0.0", tag, err)
+	}
+}
+
+func TestTimeouts(t *testing.T) {
+	forEachBackend(t, testTimeouts)
+}
+
+func testTimeouts(t *testing.T, backend string) {
+	dir, cleanup := setupTestRepo(t)
+	defer cleanup()
+
+	g := NewWithOptions("v*", dir, Options{Backend: backend, Timeouts: Timeouts{Read: time.Nanosecond}})
+	if _, err := g.GetCommitDetailsSinceTag("", ""); err == nil || !strings.Contains(err.Error(), "deadline exceeded") {
+		t.Errorf("GetCommitDetailsSinceTag() error = %v, want deadline exceeded", err)
+	}
+
+	// The read timeout must not apply to creating tags
+	if err := g.CreateTag("v3.0.0", "Release"); err != nil {
+		t.Errorf("CreateTag() error = %v", err)
+	}
+}
+
+func TestTimeoutsWithTimeout(t *testing.T) {
+	timeouts := Timeouts{Default: time.Minute, Push: time.Hour}
+
+	tests := []struct {
+		op   string
+		want time.Duration
+	}{
+		{opRead, time.Minute},
+		{opTag, time.Minute},
+		{opPush, time.Hour},
+		{opFetch, time.Minute},
+	}
+
+	for _, tt := range tests {
+		ctx, cancel := timeouts.withTimeout(context.Background(), tt.op)
+		deadline, ok := ctx.Deadline()
+		cancel()
+		if !ok {
+			t.Errorf("withTimeout(%s) has no deadline", tt.op)
+			continue
+		}
+		if remaining := time.Until(deadline); remaining > tt.want || remaining < tt.want-time.Second {
+			t.Errorf("withTimeout(%s) deadline in %v, want %v", tt.op, remaining, tt.want)
+		}
+	}
+
+	ctx, cancel := Timeouts{}.withTimeout(context.Background(), opPush)
+	defer cancel()
+	if _, ok := ctx.Deadline(); ok {
+		t.Errorf("withTimeout() without timeouts has a deadline")
+	}
+}
+
+func TestTypedErrors(t *testing.T) {
+	forEachBackend(t, testTypedErrors)
+}
+
+func testTypedErrors(t *testing.T, backend string) {
+	dir, cleanup := setupTestRepo(t)
+	defer cleanup()
+
+	g := NewWithOptions("v*", dir, Options{Backend: backend})
+
+	if _, err := g.GetLatestTag("nonexistent*"); !errors.Is(err, ErrNoMatchingTags) {
+		t.Errorf("GetLatestTag() error = %v, want ErrNoMatchingTags", err)
+	}
+
+	err := g.CreateTag("v2.0.0", "Duplicate release")
+	if !errors.Is(err, ErrTagExists) {
+		t.Errorf("CreateTag() error = %v, want ErrTagExists", err)
+	}
+
+	// The exec backend reports what git printed instead of only the exit status
+	var cmdErr *CommandError
+	if backend == "exec" {
+		if !errors.As(err, &cmdErr) || !strings.Contains(cmdErr.Stderr, "already exists") {
+			t.Errorf("CreateTag() error = %v, want git's stderr output", err)
+		}
+	}
+
+	if err := g.PushTag("v2.0.0"); err == nil {
+		t.Errorf("PushTag() without a remote expected an error")
+	} else if backend == "exec" && (!errors.As(err, &cmdErr) || cmdErr.Stderr == "") {
+		t.Errorf("PushTag() error = %v, want git's stderr output", err)
+	}
+
+	empty := t.TempDir()
+	runGit(t, empty, []string{"init", "-q"})
+	if _, err := NewWithOptions("v*", empty, Options{Backend: backend}).GetLatestTag(""); !errors.Is(err, ErrNoTags) {
+		t.Errorf("GetLatestTag() in a repository without tags error = %v, want ErrNoTags", err)
+	}
+
+	notRepo := t.TempDir()
+	if _, err := NewWithOptions("v*", notRepo, Options{Backend: backend}).GetHeadCommit(); !errors.Is(err, ErrNotARepo) {
+		t.Errorf("GetHeadCommit() outside a repository error = %v, want ErrNotARepo", err)
+	}
+}
+
+func TestClassifyStderr(t *testing.T) {
+	tests := []struct {
+		args   []string
+		stderr string
+		want   error
+	}{
+		{[]string{"rev-parse", "HEAD"}, "fatal: not a git repository (or any of the parent directories): .git", ErrNotARepo},
+		{[]string{"tag", "-a", "v1.0.0"}, "fatal: tag 'v1.0.0' already exists", ErrTagExists},
+		{[]string{"push", "origin", "v1.0.0"}, "To ../remote\n ! [rejected]        v1.0.0 -> v1.0.0 (already exists)\nerror: failed to push some refs", ErrTagExists},
+		{[]string{"-c", "push.gpgSign=false", "push", "origin"}, " ! [remote rejected] v1.0.0 -> v1.0.0 (shallow update not allowed)", ErrShallowClone},
+		{[]string{"fetch", "origin"}, "fatal: shallow update not allowed", ErrShallowClone},
+		{[]string{"fetch", "--unshallow"}, "fatal: --unshallow on a complete repository does not make sense", nil},
+		{[]string{"worktree", "add", "../wt"}, "fatal: '../wt' already exists", nil},
+		{[]string{"tag", "-d", "v9"}, "error: tag 'v9' not found.", nil},
+		{[]string{"log", "v9..HEAD"}, "fatal: ambiguous argument 'v9..HEAD'", nil},
+	}
+
+	for _, tt := range tests {
+		if got := classifyStderr(tt.args, tt.stderr); got != tt.want {
+			t.Errorf("classifyStderr(%q, %q) = %v, want %v", tt.args, tt.stderr, got, tt.want)
+		}
+	}
+}
+
+func TestPushTags(t *testing.T) {
+	forEachBackend(t, testPushTags)
+}
+
+func testPushTags(t *testing.T, backend string) {
+	dir, cleanup := setupTestRepo(t)
+	defer cleanup()
+
+	remote := t.TempDir()
+	runGit(t, remote, []string{"init", "-q", "--bare"})
+	runGit(t, dir, []string{"remote", "add", "upstream", remote})
+
+	g := NewWithOptions("v*", dir, Options{
+		Backend: backend,
+		Remote:  "upstream",
+		Push:    PushOptions{Atomic: true, Refspecs: []string{"HEAD"}},
+	})
+	branch, err := g.GetCurrentBranch()
+	if err != nil {
+		t.Fatalf("GetCurrentBranch() error = %v", err)
+	}
+
+	if err := g.PushTags([]string{"v2.0.0", "core/v1.0.0"}); err != nil {
+		t.Fatalf("PushTags() error = %v", err)
+	}
+	refs := remoteRefs(t, remote)
+	for _, ref := range []string{"refs/heads/" + branch, "refs/tags/v2.0.0", "refs/tags/core/v1.0.0"} {
+		if !refs[ref] {
+			t.Errorf("PushTags() did not push %s, remote has %v", ref, refs)
+		}
+	}
+	if refs["refs/tags/v1.0.0"] {
+		t.Errorf("PushTags() pushed v1.0.0 which was not requested")
+	}
+
+	// Move v2.0.0 so the remote rejects it, the atomic push must not push v3.0.0 either
+	runGit(t, dir,
+		[]string{"commit", "-q", "--allow-empty", "-m", "feat: next"},
+		[]string{"tag", "-f", "-a", "v2.0.0", "-m", "Moved release"},
+		[]string{"tag", "-a", "v3.0.0", "-m", "Next release"},
+	)
+	if err := g.PushTags([]string{"v3.0.0", "v2.0.0"}); err == nil {
+		t.Fatalf("PushTags() with a conflicting tag expected an error")
+	}
+	if refs := remoteRefs(t, remote); refs["refs/tags/v3.0.0"] {
+		t.Errorf("PushTags() pushed v3.0.0 although the atomic push failed")
+	}
+}
+
+func TestCreateTagTypes(t *testing.T) {
+	forEachBackend(t, testCreateTagTypes)
+}
+
+func testCreateTagTypes(t *testing.T, backend string) {
+	dir, cleanup := setupTestRepo(t)
+	defer cleanup()
+
+	annotated := NewWithOptions("v*", dir, Options{Backend: backend})
+	if err := annotated.CreateTag("v3.0.0", "Release v3.0.0\n\n## v3.0.0\n\n- feat: thing"); err != nil {
+		t.Fatalf("CreateTag() annotated error = %v", err)
+	}
+	lightweight := NewWithOptions("v*", dir, Options{Backend: backend, Lightweight: true})
+	if err := lightweight.CreateTag("v4.0.0", "ignored"); err != nil {
+		t.Fatalf("CreateTag() lightweight error = %v", err)
+	}
+
+	for tag, want := range map[string]string{"v3.0.0": "tag", "v4.0.0": "commit"} {
+		out, err := exec.Command("git", "-C", dir, "cat-file", "-t", tag).Output()
+		if err != nil {
+			t.Fatalf("Failed to read tag type: %v", err)
+		}
+		if got := strings.TrimSpace(string(out)); got != want {
+			t.Errorf("tag %s points at a %s, want %s", tag, got, want)
+		}
+	}
+
+	out, err := exec.Command("git", "-C", dir, "tag", "-l", "--format=%(contents)", "v3.0.0").Output()
+	if err != nil {
+		t.Fatalf("Failed to read tag message: %v", err)
+	}
+	if !strings.Contains(string(out), "## v3.0.0\n\n- feat: thing") {
+		t.Errorf("tag message = %q, want the release notes", out)
+	}
+}
+
+func TestDeleteTagAndReset(t *testing.T) {
+	forEachBackend(t, testDeleteTagAndReset)
+}
+
+func testDeleteTagAndReset(t *testing.T, backend string) {
+	dir, cleanup := setupTestRepo(t)
+	defer cleanup()
+
+	g := NewWithOptions("v*", dir, Options{Backend: backend})
+	before, err := g.GetHeadCommit()
+	if err != nil {
+		t.Fatalf("GetHeadCommit() error = %v", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "test.txt"), []byte("release"), 0644); err != nil {
+		t.Fatalf("Failed to update test file: %v", err)
+	}
+	runGit(t, dir, []string{"commit", "-q", "-am", "chore: release v3.0.0"})
+	if err := g.CreateTag("v3.0.0", "Release v3.0.0"); err != nil {
+		t.Fatalf("CreateTag() error = %v", err)
+	}
+
+	if err := g.DeleteTag("v3.0.0"); err != nil {
+		t.Fatalf("DeleteTag() error = %v", err)
+	}
+	if tag, err := g.GetLatestTag("v*"); err != nil || tag != "v2.0.0" {
+		t.Errorf("GetLatestTag() after DeleteTag = %v, %v, want v2.0.0", tag, err)
+	}
+	if err := g.DeleteTag("v3.0.0"); err == nil {
+		t.Errorf("DeleteTag() of a missing tag error = nil")
+	}
+
+	if err := g.Reset(before); err != nil {
+		t.Fatalf("Reset() error = %v", err)
+	}
+	if head, err := g.GetHeadCommit(); err != nil || head != before {
+		t.Errorf("GetHeadCommit() after Reset = %v, %v, want %v", head, err, before)
+	}
+	// The release changes stay in the working tree
+	if changed, err := g.HasChanges(); err != nil || !changed {
+		t.Errorf("HasChanges() after Reset = %v, %v, want true", changed, err)
+	}
+}
+
+func TestGetTags(t *testing.T) {
+	forEachBackend(t, testGetTags)
+}
+
+func testGetTags(t *testing.T, backend string) {
+	dir, cleanup := setupTestRepo(t)
+	defer cleanup()
+
+	g := NewWithOptions("v*", dir, Options{Backend: backend})
+	tags, err := g.GetTags("")
+	if err != nil {
+		t.Fatalf("GetTags() error = %v", err)
+	}
+	if want := []string{"v2.0.0", "v1.1.0", "v1.0.0"}; !reflect.DeepEqual(tags, want) {
+		t.Errorf("GetTags() = %v, want %v", tags, want)
+	}
+	if tags, err := g.GetTags("core/v*"); err != nil || !reflect.DeepEqual(tags, []string{"core/v1.0.0"}) {
+		t.Errorf("GetTags(core/v*) = %v, %v, want [core/v1.0.0]", tags, err)
+	}
+	if tags, err := g.GetTags("v9*"); err != nil || len(tags) != 0 {
+		t.Errorf("GetTags(v9*) = %v, %v, want no tags", tags, err)
+	}
+
+	if tags, err := g.GetTagsAtHead(""); err != nil || !reflect.DeepEqual(tags, []string{"v2.0.0", "v1.1.0", "v1.0.0"}) {
+		t.Errorf("GetTagsAtHead() = %v, %v, want every v* tag", tags, err)
+	}
+	runGit(t, dir,
+		[]string{"commit", "-q", "--allow-empty", "-m", "feat: next"},
+		[]string{"tag", "v3.0.0"},
+	)
+	if tags, err := g.GetTagsAtHead(""); err != nil || !reflect.DeepEqual(tags, []string{"v3.0.0"}) {
+		t.Errorf("GetTagsAtHead() after tagging = %v, %v, want [v3.0.0]", tags, err)
+	}
+}
+
+func TestUpstream(t *testing.T) {
+	forEachBackend(t, testUpstream)
+}
+
+func testUpstream(t *testing.T, backend string) {
+	origin, cleanup := setupTestRepo(t)
+	defer cleanup()
+
+	clone := t.TempDir()
+	runGit(t, clone, []string{"clone", "-q", origin, "."})
+	g := NewWithOptions("v*", clone, Options{Backend: backend})
+
+	if behind, err := g.BehindUpstream(); err != nil || behind != 0 {
+		t.Errorf("BehindUpstream() = %v, %v, want 0", behind, err)
+	}
+	runGit(t, origin,
+		[]string{"commit", "-q", "--allow-empty", "-m", "feat: one"},
+		[]string{"commit", "-q", "--allow-empty", "-m", "feat: two"},
+		[]string{"tag", "v3.0.0"},
+	)
+	runGit(t, clone, []string{"fetch", "-q", "--no-tags"})
+	if behind, err := g.BehindUpstream(); err != nil || behind != 2 {
+		t.Errorf("BehindUpstream() after fetching = %v, %v, want 2", behind, err)
+	}
+
+	for tag, want := range map[string]bool{"v2.0.0": true, "v3.0.0": true, "v4.0.0": false} {
+		if exists, err := g.HasRemoteTag(tag); err != nil || exists != want {
+			t.Errorf("HasRemoteTag(%s) = %v, %v, want %v", tag, exists, err, want)
+		}
+	}
+
+	runGit(t, clone, []string{"checkout", "-q", "--detach"})
+	if _, err := g.BehindUpstream(); !errors.Is(err, ErrNoUpstream) {
+		t.Errorf("BehindUpstream() on a detached HEAD error = %v, want ErrNoUpstream", err)
+	}
+}
+
+// remoteRefs lists the refs of a repository
+func remoteRefs(t *testing.T, dir string) map[string]bool {
+	t.Helper()
+	out, err := exec.Command("git", "-C", dir, "for-each-ref", "--format=%(refname)").Output()
+	if err != nil {
+		t.Fatalf("Failed to list refs: %v", err)
+	}
+	refs := make(map[string]bool)
+	for _, ref := range strings.Fields(string(out)) {
+		refs[ref] = true
+	}
+	return refs
+}
+
+func TestSignedTags(t *testing.T) {
+	if _, err := exec.LookPath("ssh-keygen"); err != nil {
+		t.Skip("ssh-keygen is not installed")
+	}
+
+	dir, cleanup := setupTestRepo(t)
+	defer cleanup()
+
+	keyDir := t.TempDir()
+	key := filepath.Join(keyDir, "release")
+	if out, err := exec.Command("ssh-keygen", "-q", "-t", "ed25519", "-N", "", "-C", "release", "-f", key).CombinedOutput(); err != nil {
+		t.Fatalf("Failed to generate signing key: %v\n%s", err, out)
+	}
+	pub, err := os.ReadFile(key + ".pub")
+	if err != nil {
+		t.Fatalf("Failed to read public key: %v", err)
+	}
+	allowedSigners := filepath.Join(keyDir, "allowed_signers")
+	if err := os.WriteFile(allowedSigners, []byte("test@example.com "+string(pub)), 0644); err != nil {
+		t.Fatalf("Failed to write allowed signers: %v", err)
+	}
+
+	signing := SigningOptions{Format: "ssh", Key: key, AllowedSigners: allowedSigners}
+	g := NewWithOptions("v*", dir, Options{Signing: signing})
+
+	if err := g.CreateTag("v3.0.0", "Signed release"); err != nil {
+		t.Fatalf("CreateTag() error = %v", err)
+	}
+	if err := g.VerifyTag("v3.0.0"); err != nil {
+		t.Errorf("VerifyTag() on a signed tag error = %v", err)
+	}
+	if err := g.VerifyTag("v2.0.0"); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("VerifyTag() on an unsigned tag error = %v, want ErrInvalidSignature", err)
+	}
+
+	signing.Strict = true
+	strict := NewWithOptions("v*", dir, Options{Signing: signing})
+	if tag, err := strict.GetLatestTag("v*"); err != nil || tag != "v3.0.0" {
+		t.Errorf("GetLatestTag() with strict signing = %v, %v, want v3.0.0", tag, err)
+	}
+	if _, err := strict.GetLatestTag("core/v*"); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("GetLatestTag() with strict signing on an unsigned tag error = %v, want ErrInvalidSignature", err)
+	}
+
+	native := NewWithOptions("v*", dir, Options{Backend: "go-git", Signing: signing})
+	if err := native.CreateTag("v4.0.0", "Signed release"); err == nil {
+		t.Errorf("CreateTag() with the go-git backend expected an error for signed tags")
+	}
+}
@@ -0,0 +1,1107 @@
+package git
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	gogit "github.com/go-git/go-git/v5"
+	gitconfig "github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/transport/http"
+)
+
+// goGit implements Interface natively with go-git, without a git binary
+type goGit struct {
+	tagPattern string
+	workDir    string
+	opts       Options
+
+	once    sync.Once
+	repo    *gogit.Repository
+	openErr error
+}
+
+func newGoGit(tagPattern, workDir string, opts Options) Interface {
+	return &goGit{
+		tagPattern: tagPattern,
+		workDir:    workDir,
+		opts:       opts,
+	}
+}
+
+// open opens the repository containing the working directory once and reuses it
+func (g *goGit) open() (*gogit.Repository, error) {
+	g.once.Do(func() {
+		dir := g.workDir
+		if dir == "" {
+			dir = "."
+		}
+		g.repo, g.openErr = gogit.PlainOpenWithOptions(dir, &gogit.PlainOpenOptions{DetectDotGit: true})
+	})
+	return g.repo, g.openErr
+}
+
+// GetLatestTag returns the latest tag that matches the pattern
+func (g *goGit) GetLatestTag(pattern string) (string, error) {
+	return g.GetLatestTagContext(context.Background(), pattern)
+}
+
+// GetLatestTagContext returns the latest tag that matches the pattern
+func (g *goGit) GetLatestTagContext(ctx context.Context, pattern string) (string, error) {
+	ctx, cancel := g.opts.Timeouts.withTimeout(ctx, opRead)
+	defer cancel()
+
+	repo, err := g.open()
+	if err != nil {
+		return "", fmt.Errorf("failed to get tags: %w", goGitError(err))
+	}
+
+	refs, err := repo.Tags()
+	if err != nil {
+		return "", fmt.Errorf("failed to get tags: %w", goGitError(err))
+	}
+	var tags []string
+	err = refs.ForEach(func(ref *plumbing.Reference) error {
+		tags = append(tags, ref.Name().Short())
+		return ctx.Err()
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to get tags: %w", goGitError(err))
+	}
+	if len(tags) == 0 {
+		return "", ErrNoTags
+	}
+
+	// Match the order of git tag --sort=-v:refname
+	sort.Slice(tags, func(i, j int) bool {
+		return versionLess(tags[j], tags[i])
+	})
+
+	// If no pattern is provided, use the instance's pattern
+	if pattern == "" {
+		pattern = g.tagPattern
+	}
+
+	// Find the latest matching tag
+	for _, tag := range tags {
+		matched, err := filepath.Match(pattern, tag)
+		if err != nil {
+			return "", fmt.Errorf("invalid pattern: %v", err)
+		}
+		if matched {
+			if g.opts.Signing.Strict {
+				if err := g.VerifyTagContext(ctx, tag); err != nil {
+					return "", err
+				}
+			}
+			return tag, nil
+		}
+	}
+
+	return "", ErrNoMatchingTags
+}
+
+// GetTags returns the tags matching the pattern, see GetTagsContext
+func (g *goGit) GetTags(pattern string) ([]string, error) {
+	return g.GetTagsContext(context.Background(), pattern)
+}
+
+// GetTagsContext returns the tags matching the pattern, newest version first.
+// An empty pattern uses the tag pattern of the instance.
+func (g *goGit) GetTagsContext(ctx context.Context, pattern string) ([]string, error) {
+	ctx, cancel := g.opts.Timeouts.withTimeout(ctx, opRead)
+	defer cancel()
+
+	repo, err := g.open()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tags: %w", goGitError(err))
+	}
+	refs, err := repo.Tags()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tags: %w", goGitError(err))
+	}
+	if pattern == "" {
+		pattern = g.tagPattern
+	}
+
+	var tags []string
+	err = refs.ForEach(func(ref *plumbing.Reference) error {
+		tag := ref.Name().Short()
+		matched, err := filepath.Match(pattern, tag)
+		if err != nil {
+			return fmt.Errorf("invalid pattern: %v", err)
+		}
+		if matched {
+			tags = append(tags, tag)
+		}
+		return ctx.Err()
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tags: %w", goGitError(err))
+	}
+	sort.Slice(tags, func(i, j int) bool {
+		return versionLess(tags[j], tags[i])
+	})
+	return tags, nil
+}
+
+// GetTagsAtHead returns the tags matching the pattern that point at HEAD, see GetTagsAtHeadContext
+func (g *goGit) GetTagsAtHead(pattern string) ([]string, error) {
+	return g.GetTagsAtHeadContext(context.Background(), pattern)
+}
+
+// GetTagsAtHeadContext returns the tags matching the pattern that point at HEAD,
+// newest version first. An empty pattern uses the tag pattern of the instance.
+func (g *goGit) GetTagsAtHeadContext(ctx context.Context, pattern string) ([]string, error) {
+	tags, err := g.GetTagsContext(ctx, pattern)
+	if err != nil {
+		return nil, err
+	}
+	repo, err := g.open()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tags: %w", goGitError(err))
+	}
+	head, err := repo.Head()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tags: %w", goGitError(err))
+	}
+
+	var atHead []string
+	for _, tag := range tags {
+		hash, err := repo.ResolveRevision(plumbing.Revision(tag + "^{commit}"))
+		if err != nil {
+			return nil, fmt.Errorf("failed to get tags: %w", goGitError(err))
+		}
+		if *hash == head.Hash() {
+			atHead = append(atHead, tag)
+		}
+	}
+	return atHead, nil
+}
+
+// GetCommitsSinceTag returns all commits since the given tag
+func (g *goGit) GetCommitsSinceTag(tag string) ([]string, error) {
+	return g.GetCommitsSinceTagContext(context.Background(), tag)
+}
+
+// GetCommitsSinceTagContext returns all commits since the given tag
+func (g *goGit) GetCommitsSinceTagContext(ctx context.Context, tag string) ([]string, error) {
+	return g.GetCommitsSinceTagForPathContext(ctx, tag, "")
+}
+
+// GetCommitsSinceTagForPath returns all commits since the given tag for the specified path
+func (g *goGit) GetCommitsSinceTagForPath(tag, path string) ([]string, error) {
+	return g.GetCommitsSinceTagForPathContext(context.Background(), tag, path)
+}
+
+// GetCommitsSinceTagForPathContext returns all commits since the given tag for the specified path
+func (g *goGit) GetCommitsSinceTagForPathContext(ctx context.Context, tag, path string) ([]string, error) {
+	commits, err := g.GetCommitDetailsSinceTagContext(ctx, tag, path)
+	if err != nil {
+		return nil, err
+	}
+
+	// Build the same output as git log --format=%B so messages are split identically
+	var out strings.Builder
+	for _, commit := range commits {
+		out.WriteString(commit.Message)
+		out.WriteString("\n\n")
+	}
+	return splitCommitMessages(out.String()), nil
+}
+
+// GetCommitDetailsSinceTag returns the hash, parents, author, message and changed files of every
+// commit since the given tag, newest first. An empty tag returns the whole history and
+// an empty path does not restrict the commits to a path.
+func (g *goGit) GetCommitDetailsSinceTag(tag, path string) ([]Commit, error) {
+	return g.GetCommitDetailsSinceTagContext(context.Background(), tag, path)
+}
+
+// GetCommitDetailsSinceTagContext returns the details of every commit since the given tag, see GetCommitDetailsSinceTag
+func (g *goGit) GetCommitDetailsSinceTagContext(ctx context.Context, tag, path string) ([]Commit, error) {
+	ctx, cancel := g.opts.Timeouts.withTimeout(ctx, opRead)
+	defer cancel()
+
+	commits, err := g.walk(ctx, tag, path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get commits: %w", goGitError(err))
+	}
+
+	details := make([]Commit, 0, len(commits))
+	for _, c := range commits {
+		if err := ctx.Err(); err != nil {
+			return nil, fmt.Errorf("failed to get commits: %w", goGitError(err))
+		}
+		files, err := changedFiles(c)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get commits: %w", goGitError(err))
+		}
+
+		commit := Commit{
+			Hash:    c.Hash.String(),
+			Parents: make([]string, 0, len(c.ParentHashes)),
+			Author:  fmt.Sprintf("%s <%s>", c.Author.Name, c.Author.Email),
+			Message: strings.TrimSpace(c.Message),
+		}
+		for _, parent := range c.ParentHashes {
+			commit.Parents = append(commit.Parents, parent.String())
+		}
+		for _, file := range files {
+			if path == "" || inPath(file, path) {
+				commit.Files = append(commit.Files, file)
+			}
+		}
+		details = append(details, commit)
+	}
+	return details, nil
+}
+
+// GetFirstCommit returns the hash of the first commit
+func (g *goGit) GetFirstCommit() (string, error) {
+	return g.GetFirstCommitContext(context.Background())
+}
+
+// GetFirstCommitContext returns the hash of the first commit
+func (g *goGit) GetFirstCommitContext(ctx context.Context) (string, error) {
+	ctx, cancel := g.opts.Timeouts.withTimeout(ctx, opRead)
+	defer cancel()
+
+	commits, err := g.walk(ctx, "", "")
+	if err != nil {
+		return "", fmt.Errorf("failed to get first commit: %w", goGitError(err))
+	}
+
+	var roots []string
+	for _, c := range commits {
+		if c.NumParents() == 0 {
+			roots = append(roots, c.Hash.String())
+		}
+	}
+	return strings.Join(roots, "\n"), nil
+}
+
+// GetHeadCommit returns the full hash of the commit HEAD points to
+func (g *goGit) GetHeadCommit() (string, error) {
+	return g.GetHeadCommitContext(context.Background())
+}
+
+// GetHeadCommitContext returns the full hash of the commit HEAD points to
+func (g *goGit) GetHeadCommitContext(ctx context.Context) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", fmt.Errorf("failed to get head commit: %w", goGitError(err))
+	}
+	repo, err := g.open()
+	if err != nil {
+		return "", fmt.Errorf("failed to get head commit: %w", goGitError(err))
+	}
+	head, err := repo.Head()
+	if err != nil {
+		return "", fmt.Errorf("failed to get head commit: %w", goGitError(err))
+	}
+	return head.Hash().String(), nil
+}
+
+// CountCommitsSinceTag returns the number of commits since the given tag, optionally
+// restricted to a path. An empty tag counts the whole history.
+func (g *goGit) CountCommitsSinceTag(tag, path string) (int, error) {
+	return g.CountCommitsSinceTagContext(context.Background(), tag, path)
+}
+
+// CountCommitsSinceTagContext returns the number of commits since the given tag, see CountCommitsSinceTag
+func (g *goGit) CountCommitsSinceTagContext(ctx context.Context, tag, path string) (int, error) {
+	ctx, cancel := g.opts.Timeouts.withTimeout(ctx, opRead)
+	defer cancel()
+
+	commits, err := g.walk(ctx, tag, path)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count commits: %w", goGitError(err))
+	}
+	return len(commits), nil
+}
+
+// HasChanges returns true if there are uncommitted changes
+func (g *goGit) HasChanges() (bool, error) {
+	return g.HasChangesContext(context.Background())
+}
+
+// HasChangesContext returns true if there are uncommitted changes.
+// go-git cannot interrupt a status scan, so the context is only checked before it starts.
+func (g *goGit) HasChangesContext(ctx context.Context) (bool, error) {
+	if err := ctx.Err(); err != nil {
+		return false, fmt.Errorf("failed to get status: %w", goGitError(err))
+	}
+	repo, err := g.open()
+	if err != nil {
+		return false, fmt.Errorf("failed to get status: %w", goGitError(err))
+	}
+	worktree, err := repo.Worktree()
+	if err != nil {
+		return false, fmt.Errorf("failed to get status: %w", goGitError(err))
+	}
+	status, err := worktree.Status()
+	if err != nil {
+		return false, fmt.Errorf("failed to get status: %w", goGitError(err))
+	}
+	return !status.IsClean(), nil
+}
+
+// IsClean returns true if the repository has no uncommitted changes
+func (g *goGit) IsClean() (bool, error) {
+	return g.IsCleanContext(context.Background())
+}
+
+// IsCleanContext returns true if the repository has no uncommitted changes
+func (g *goGit) IsCleanContext(ctx context.Context) (bool, error) {
+	hasChanges, err := g.HasChangesContext(ctx)
+	if err != nil {
+		return false, err
+	}
+	return !hasChanges, nil
+}
+
+// CreateTag creates a new git tag on HEAD, see CreateTagContext
+func (g *goGit) CreateTag(tag string, message string) error {
+	return g.CreateTagContext(context.Background(), tag, message)
+}
+
+// CreateTagContext creates a new annotated git tag on HEAD, or a lightweight one when configured
+func (g *goGit) CreateTagContext(ctx context.Context, tag string, message string) error {
+	if g.opts.Signing.Format != "" {
+		return fmt.Errorf("failed to create tag: %w", errSigningUnsupported)
+	}
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("failed to create tag: %w", goGitError(err))
+	}
+	repo, err := g.open()
+	if err != nil {
+		return fmt.Errorf("failed to create tag: %w", goGitError(err))
+	}
+	head, err := repo.Head()
+	if err != nil {
+		return fmt.Errorf("failed to create tag: %w", goGitError(err))
+	}
+	var opts *gogit.CreateTagOptions
+	if !g.opts.Lightweight {
+		opts = &gogit.CreateTagOptions{Message: message}
+	}
+	if _, err := repo.CreateTag(tag, head.Hash(), opts); err != nil {
+		return fmt.Errorf("failed to create tag: %w", goGitError(err))
+	}
+	return nil
+}
+
+// errSigningUnsupported is returned when signing or verifying tags with the go-git backend,
+// which cannot use the gpg agent or ssh keys configured for git
+var errSigningUnsupported = errors.New("signed tags are not supported by the go-git backend, use the exec backend")
+
+// VerifyTag checks the signature of a tag, which the go-git backend does not support
+func (g *goGit) VerifyTag(tag string) error {
+	return g.VerifyTagContext(context.Background(), tag)
+}
+
+// VerifyTagContext checks the signature of a tag, which the go-git backend does not support
+func (g *goGit) VerifyTagContext(_ context.Context, tag string) error {
+	return fmt.Errorf("failed to verify tag %s: %w", tag, errSigningUnsupported)
+}
+
+// DeleteTag deletes a local tag, see DeleteTagContext
+func (g *goGit) DeleteTag(tag string) error {
+	return g.DeleteTagContext(context.Background(), tag)
+}
+
+// DeleteTagContext deletes a local tag. Tags already pushed to the remote are left alone.
+func (g *goGit) DeleteTagContext(ctx context.Context, tag string) error {
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("failed to delete tag %s: %w", tag, goGitError(err))
+	}
+	repo, err := g.open()
+	if err != nil {
+		return fmt.Errorf("failed to delete tag %s: %w", tag, goGitError(err))
+	}
+	if err := repo.DeleteTag(tag); err != nil {
+		return fmt.Errorf("failed to delete tag %s: %w", tag, goGitError(err))
+	}
+	return nil
+}
+
+// PushTag pushes a tag to the remote repository.
+// GitHub remotes authenticate with the GITHUB_TOKEN environment variable, see githubAuth.
+func (g *goGit) PushTag(tag string) error {
+	return g.PushTagContext(context.Background(), tag)
+}
+
+// PushTagContext pushes a tag to the remote repository, see PushTag
+func (g *goGit) PushTagContext(ctx context.Context, tag string) error {
+	return g.PushTagsContext(ctx, []string{tag})
+}
+
+// HasRemoteTag reports whether the tag exists on the remote, see HasRemoteTagContext
+func (g *goGit) HasRemoteTag(tag string) (bool, error) {
+	return g.HasRemoteTagContext(context.Background(), tag)
+}
+
+// HasRemoteTagContext reports whether the tag exists on the configured remote.
+// GitHub remotes authenticate with the GITHUB_TOKEN environment variable, see githubAuth.
+func (g *goGit) HasRemoteTagContext(ctx context.Context, tag string) (bool, error) {
+	ctx, cancel := g.opts.Timeouts.withTimeout(ctx, opFetch)
+	defer cancel()
+
+	repo, err := g.open()
+	if err != nil {
+		return false, fmt.Errorf("failed to list remote tags: %w", goGitError(err))
+	}
+	remote, err := repo.Remote(g.opts.remote())
+	if err != nil {
+		return false, fmt.Errorf("failed to list remote tags: %w", goGitError(err))
+	}
+	opts := &gogit.ListOptions{}
+	if auth := githubAuth(repo, g.opts.remote()); auth != nil {
+		opts.Auth = auth
+	}
+
+	refs, err := remote.ListContext(ctx, opts)
+	if err != nil {
+		return false, fmt.Errorf("failed to list remote tags: %w", goGitError(err))
+	}
+	name := plumbing.NewTagReferenceName(tag)
+	for _, ref := range refs {
+		if ref.Name() == name {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// PushTags pushes the tags and the configured refspecs to the remote in a single push
+func (g *goGit) PushTags(tags []string) error {
+	return g.PushTagsContext(context.Background(), tags)
+}
+
+// PushTagsContext pushes the tags and the configured refspecs to the remote in a single push
+func (g *goGit) PushTagsContext(ctx context.Context, tags []string) error {
+	ctx, cancel := g.opts.Timeouts.withTimeout(ctx, opPush)
+	defer cancel()
+
+	repo, err := g.open()
+	if err != nil {
+		return fmt.Errorf("failed to push tag: %w", goGitError(err))
+	}
+
+	opts := &gogit.PushOptions{
+		RemoteName: g.opts.remote(),
+		Atomic:     g.opts.Push.Atomic,
+		FollowTags: g.opts.Push.FollowTags,
+	}
+	for _, tag := range tags {
+		opts.RefSpecs = append(opts.RefSpecs, gitconfig.RefSpec(tagRefSpec(tag)))
+	}
+	for _, spec := range g.opts.Push.Refspecs {
+		refSpec, err := expandRefSpec(repo, spec)
+		if err != nil {
+			return fmt.Errorf("failed to push tag: %w", err)
+		}
+		opts.RefSpecs = append(opts.RefSpecs, refSpec)
+	}
+	if auth := githubAuth(repo, opts.RemoteName); auth != nil {
+		opts.Auth = auth
+	}
+
+	if err := repo.PushContext(ctx, opts); err != nil && !errors.Is(err, gogit.NoErrAlreadyUpToDate) {
+		return fmt.Errorf("failed to push tag: %w", goGitError(err))
+	}
+	return nil
+}
+
+// expandRefSpec turns a refspec as accepted by git push, such as "HEAD" or "main:release",
+// into the fully qualified form go-git requires
+func expandRefSpec(repo *gogit.Repository, spec string) (gitconfig.RefSpec, error) {
+	force := strings.HasPrefix(spec, "+")
+	src, dst, found := strings.Cut(strings.TrimPrefix(spec, "+"), ":")
+
+	src, err := expandRefName(repo, src)
+	if err != nil {
+		return "", err
+	}
+	if !found || dst == "HEAD" {
+		dst = src
+	} else if !strings.HasPrefix(dst, "refs/") {
+		// An unqualified destination gets the same kind of ref as the source
+		dst = strings.TrimSuffix(src, plumbing.ReferenceName(src).Short()) + dst
+	}
+
+	refSpec := gitconfig.RefSpec(src + ":" + dst)
+	if force {
+		refSpec = "+" + refSpec
+	}
+	if err := refSpec.Validate(); err != nil {
+		return "", fmt.Errorf("invalid refspec %q: %w", spec, err)
+	}
+	return refSpec, nil
+}
+
+// expandRefName resolves HEAD and short branch or tag names to full reference names
+func expandRefName(repo *gogit.Repository, name string) (string, error) {
+	if strings.HasPrefix(name, "refs/") {
+		return name, nil
+	}
+	if name == "HEAD" {
+		head, err := repo.Head()
+		if err != nil {
+			return "", err
+		}
+		if !head.Name().IsBranch() {
+			return "", fmt.Errorf("cannot push HEAD: not on a branch")
+		}
+		return head.Name().String(), nil
+	}
+	for _, ref := range []plumbing.ReferenceName{plumbing.NewBranchReferenceName(name), plumbing.NewTagReferenceName(name)} {
+		if _, err := repo.Reference(ref, false); err == nil {
+			return ref.String(), nil
+		}
+	}
+	return "", fmt.Errorf("unknown ref %q", name)
+}
+
+// githubAuth returns the credentials for the named remote taken from the GITHUB_TOKEN
+// environment variable, as set in GitHub Actions. The token is only sent to HTTPS remotes
+// on github.com or the GitHub Enterprise server in GITHUB_SERVER_URL, so it never
+// leaks to other hosts. It returns nil when the remote gets no credentials.
+func githubAuth(repo *gogit.Repository, name string) *http.BasicAuth {
+	token := os.Getenv("GITHUB_TOKEN")
+	if token == "" {
+		return nil
+	}
+	remote, err := repo.Remote(name)
+	if err != nil || len(remote.Config().URLs) == 0 {
+		return nil
+	}
+	u, err := url.Parse(remote.Config().URLs[0])
+	if err != nil || (u.Scheme != "https" && u.Scheme != "http") {
+		return nil
+	}
+
+	hosts := []string{"github.com"}
+	if server, err := url.Parse(os.Getenv("GITHUB_SERVER_URL")); err == nil && server.Hostname() != "" {
+		hosts = append(hosts, server.Hostname())
+	}
+	for _, host := range hosts {
+		if strings.EqualFold(u.Hostname(), host) {
+			return &http.BasicAuth{Username: "x-access-token", Password: token}
+		}
+	}
+	return nil
+}
+
+// GetCurrentBranch returns the name of the current branch, or HEAD when detached
+func (g *goGit) GetCurrentBranch() (string, error) {
+	return g.GetCurrentBranchContext(context.Background())
+}
+
+// GetCurrentBranchContext returns the name of the current branch, or HEAD when detached
+func (g *goGit) GetCurrentBranchContext(ctx context.Context) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", fmt.Errorf("failed to get current branch: %w", goGitError(err))
+	}
+	repo, err := g.open()
+	if err != nil {
+		return "", fmt.Errorf("failed to get current branch: %w", goGitError(err))
+	}
+	head, err := repo.Head()
+	if err != nil {
+		return "", fmt.Errorf("failed to get current branch: %w", goGitError(err))
+	}
+	if !head.Name().IsBranch() {
+		return "HEAD", nil
+	}
+	return head.Name().Short(), nil
+}
+
+// GetCurrentVersion returns the current version based on the latest tag
+func (g *goGit) GetCurrentVersion() (string, error) {
+	return g.GetCurrentVersionContext(context.Background())
+}
+
+// GetCurrentVersionContext returns the current version based on the latest tag
+func (g *goGit) GetCurrentVersionContext(ctx context.Context) (string, error) {
+	return g.GetLatestTagContext(ctx, g.tagPattern)
+}
+
+// GetCommitsSinceVersion returns all commits since the given version
+func (g *goGit) GetCommitsSinceVersion(version string) ([]string, error) {
+	return g.GetCommitsSinceVersionContext(context.Background(), version)
+}
+
+// GetCommitsSinceVersionContext returns all commits since the given version
+func (g *goGit) GetCommitsSinceVersionContext(ctx context.Context, version string) ([]string, error) {
+	return g.GetCommitsSinceTagContext(ctx, version)
+}
+
+// IsShallow reports whether the repository is a shallow clone
+func (g *goGit) IsShallow() (bool, error) {
+	return g.IsShallowContext(context.Background())
+}
+
+// IsShallowContext reports whether the repository is a shallow clone
+func (g *goGit) IsShallowContext(ctx context.Context) (bool, error) {
+	if err := ctx.Err(); err != nil {
+		return false, fmt.Errorf("failed to check for shallow clone: %w", err)
+	}
+	repo, err := g.open()
+	if err != nil {
+		return false, fmt.Errorf("failed to check for shallow clone: %w", goGitError(err))
+	}
+	return isShallow(repo), nil
+}
+
+// IsAncestor reports whether the tag is reachable from HEAD
+func (g *goGit) IsAncestor(tag string) (bool, error) {
+	return g.IsAncestorContext(context.Background(), tag)
+}
+
+// IsAncestorContext reports whether the tag is reachable from HEAD.
+// History cut off by a shallow clone is treated as unreachable.
+func (g *goGit) IsAncestorContext(ctx context.Context, tag string) (bool, error) {
+	ctx, cancel := g.opts.Timeouts.withTimeout(ctx, opRead)
+	defer cancel()
+
+	repo, err := g.open()
+	if err != nil {
+		return false, fmt.Errorf("failed to check ancestry of %s: %w", tag, goGitError(err))
+	}
+	hash, err := repo.ResolveRevision(plumbing.Revision(tag + "^{commit}"))
+	if err != nil {
+		return false, fmt.Errorf("failed to check ancestry of %s: %w", tag, err)
+	}
+
+	head, err := repo.Head()
+	if err != nil {
+		return false, fmt.Errorf("failed to check ancestry of %s: %w", tag, goGitError(err))
+	}
+	missing, err := shallowBoundary(repo)
+	if err != nil {
+		return false, fmt.Errorf("failed to check ancestry of %s: %w", tag, err)
+	}
+
+	// The tag is an ancestor when none of its history is missing from HEAD
+	commits, err := revList(ctx, repo, []plumbing.Hash{*hash}, []plumbing.Hash{head.Hash()}, missing)
+	if errors.Is(err, plumbing.ErrObjectNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to check ancestry of %s: %w", tag, err)
+	}
+	return len(commits) == 0, nil
+}
+
+// infiniteDepth is the depth git requests when unshallowing a repository
+const infiniteDepth = 0x7fffffff
+
+// Deepen fetches all tags and depth more commits of history from the remote, see DeepenContext
+func (g *goGit) Deepen(depth int) error {
+	return g.DeepenContext(context.Background(), depth)
+}
+
+// DeepenContext fetches all tags and depth more commits of history from the remote.
+// A depth of zero or less fetches the complete history.
+func (g *goGit) DeepenContext(ctx context.Context, depth int) error {
+	ctx, cancel := g.opts.Timeouts.withTimeout(ctx, opFetch)
+	defer cancel()
+
+	repo, err := g.open()
+	if err != nil {
+		return fmt.Errorf("failed to fetch history: %w", goGitError(err))
+	}
+
+	// go-git fetches to an absolute depth, so a shallow clone adds the history already present
+	opts := &gogit.FetchOptions{
+		RemoteName: g.opts.remote(),
+		Tags:       gogit.AllTags,
+		Force:      true,
+		Depth:      infiniteDepth,
+	}
+	if depth > 0 && isShallow(repo) {
+		head, err := repo.Head()
+		if err != nil {
+			return fmt.Errorf("failed to fetch history: %w", goGitError(err))
+		}
+		missing, err := shallowBoundary(repo)
+		if err != nil {
+			return fmt.Errorf("failed to fetch history: %w", err)
+		}
+		present, err := historyDepth(ctx, repo, head.Hash(), missing)
+		if err != nil {
+			return fmt.Errorf("failed to fetch history: %w", err)
+		}
+		opts.Depth = present + depth
+	}
+	if auth := githubAuth(repo, opts.RemoteName); auth != nil {
+		opts.Auth = auth
+	}
+
+	if err := repo.FetchContext(ctx, opts); err != nil && !errors.Is(err, gogit.NoErrAlreadyUpToDate) {
+		return fmt.Errorf("failed to fetch history: %w", err)
+	}
+	return nil
+}
+
+// BehindUpstream counts the commits of the upstream branch missing from HEAD, see BehindUpstreamContext
+func (g *goGit) BehindUpstream() (int, error) {
+	return g.BehindUpstreamContext(context.Background())
+}
+
+// BehindUpstreamContext counts the commits of the current branch's upstream that HEAD does
+// not contain, as last fetched. It returns an error wrapping ErrNoUpstream when HEAD is
+// detached or the branch does not track an upstream branch.
+func (g *goGit) BehindUpstreamContext(ctx context.Context) (int, error) {
+	ctx, cancel := g.opts.Timeouts.withTimeout(ctx, opRead)
+	defer cancel()
+
+	repo, err := g.open()
+	if err != nil {
+		return 0, fmt.Errorf("failed to compare with upstream: %w", goGitError(err))
+	}
+	head, err := repo.Head()
+	if err != nil {
+		return 0, fmt.Errorf("failed to compare with upstream: %w", goGitError(err))
+	}
+	if !head.Name().IsBranch() {
+		return 0, fmt.Errorf("failed to compare with upstream: %w: HEAD is detached", ErrNoUpstream)
+	}
+	branch, err := repo.Branch(head.Name().Short())
+	if err != nil || branch.Remote == "" || branch.Merge == "" {
+		return 0, fmt.Errorf("failed to compare with upstream: %w for branch %s", ErrNoUpstream, head.Name().Short())
+	}
+	upstream, err := repo.Reference(plumbing.NewRemoteReferenceName(branch.Remote, branch.Merge.Short()), true)
+	if err != nil {
+		return 0, fmt.Errorf("failed to compare with upstream: %w for branch %s: %w", ErrNoUpstream, head.Name().Short(), err)
+	}
+
+	missing, err := shallowBoundary(repo)
+	if err != nil {
+		return 0, fmt.Errorf("failed to compare with upstream: %w", goGitError(err))
+	}
+	commits, err := revList(ctx, repo, []plumbing.Hash{upstream.Hash()}, []plumbing.Hash{head.Hash()}, missing)
+	if err != nil {
+		return 0, fmt.Errorf("failed to compare with upstream: %w", goGitError(err))
+	}
+	return len(commits), nil
+}
+
+// Reset moves HEAD and the index to the commit, see ResetContext
+func (g *goGit) Reset(commit string) error {
+	return g.ResetContext(context.Background(), commit)
+}
+
+// ResetContext moves HEAD and the index to the commit. The working tree is left as it is,
+// so resetting a release commit keeps its changes as uncommitted modifications.
+func (g *goGit) ResetContext(ctx context.Context, commit string) error {
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("failed to reset to %s: %w", commit, goGitError(err))
+	}
+	repo, err := g.open()
+	if err != nil {
+		return fmt.Errorf("failed to reset to %s: %w", commit, goGitError(err))
+	}
+	hash, err := repo.ResolveRevision(plumbing.Revision(commit))
+	if err != nil {
+		return fmt.Errorf("failed to reset to %s: %w", commit, goGitError(err))
+	}
+	worktree, err := repo.Worktree()
+	if err != nil {
+		return fmt.Errorf("failed to reset to %s: %w", commit, goGitError(err))
+	}
+	if err := worktree.Reset(&gogit.ResetOptions{Commit: *hash, Mode: gogit.MixedReset}); err != nil {
+		return fmt.Errorf("failed to reset to %s: %w", commit, goGitError(err))
+	}
+	return nil
+}
+
+// walk returns the commits reachable from HEAD but not from tag, newest first,
+// like git log tag..HEAD. Commits not touching path are skipped when path is set.
+// The walk stops with the context error when the context is done.
+func (g *goGit) walk(ctx context.Context, tag, path string) ([]*object.Commit, error) {
+	repo, err := g.open()
+	if err != nil {
+		return nil, err
+	}
+
+	commits, err := g.walkRepo(ctx, repo, tag, path)
+	// History missing from a shallow clone shows up as objects that cannot be found
+	if errors.Is(err, plumbing.ErrObjectNotFound) && isShallow(repo) {
+		return nil, fmt.Errorf("%w: %w", ErrShallowClone, err)
+	}
+	return commits, err
+}
+
+// isShallow reports whether the repository is a shallow clone
+func isShallow(repo *gogit.Repository) bool {
+	shallow, err := repo.Storer.Shallow()
+	return err == nil && len(shallow) > 0
+}
+
+// shallowBoundary returns the parents of the commits at the edge of a shallow clone
+// that are not in the repository
+func shallowBoundary(repo *gogit.Repository) (map[plumbing.Hash]bool, error) {
+	shallow, err := repo.Storer.Shallow()
+	if err != nil {
+		return nil, err
+	}
+
+	missing := make(map[plumbing.Hash]bool)
+	for _, hash := range shallow {
+		c, err := repo.CommitObject(hash)
+		if errors.Is(err, plumbing.ErrObjectNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		for _, parent := range c.ParentHashes {
+			if repo.Storer.HasEncodedObject(parent) != nil {
+				missing[parent] = true
+			}
+		}
+	}
+	return missing, nil
+}
+
+// graft drops the parents missing from a shallow clone, so commits at the
+// edge of the clone are root commits like they are for git
+func graft(c *object.Commit, missing map[plumbing.Hash]bool) *object.Commit {
+	if len(missing) == 0 {
+		return c
+	}
+	parents := make([]plumbing.Hash, 0, len(c.ParentHashes))
+	for _, parent := range c.ParentHashes {
+		if !missing[parent] {
+			parents = append(parents, parent)
+		}
+	}
+	c.ParentHashes = parents
+	return c
+}
+
+// goGitError wraps go-git errors in the matching sentinel errors of this package
+func goGitError(err error) error {
+	switch {
+	case errors.Is(err, gogit.ErrRepositoryNotExists):
+		return fmt.Errorf("%w: %w", ErrNotARepo, err)
+	case errors.Is(err, gogit.ErrTagExists):
+		return fmt.Errorf("%w: %w", ErrTagExists, err)
+	case err != nil && strings.Contains(err.Error(), "non-fast-forward update: refs/tags/"):
+		// The remote already has the tag pointing at another commit
+		return fmt.Errorf("%w: %w", ErrTagExists, err)
+	}
+	return err
+}
+
+// walkRepo implements walk on the opened repository
+func (g *goGit) walkRepo(ctx context.Context, repo *gogit.Repository, tag, path string) ([]*object.Commit, error) {
+	head, err := repo.Head()
+	if err != nil {
+		return nil, err
+	}
+	missing, err := shallowBoundary(repo)
+	if err != nil {
+		return nil, err
+	}
+	var exclude []plumbing.Hash
+	if tag != "" {
+		hash, err := repo.ResolveRevision(plumbing.Revision(tag + "^{commit}"))
+		if err != nil {
+			return nil, fmt.Errorf("unknown revision %s: %w", tag, err)
+		}
+		exclude = append(exclude, *hash)
+	}
+
+	commits, err := revList(ctx, repo, []plumbing.Hash{head.Hash()}, exclude, missing)
+	if err != nil {
+		return nil, err
+	}
+
+	if g.opts.FirstParent {
+		included := make(map[plumbing.Hash]*object.Commit, len(commits))
+		for _, c := range commits {
+			included[c.Hash] = c
+		}
+		commits = commits[:0]
+		for c := included[head.Hash()]; c != nil; {
+			commits = append(commits, c)
+			if c.NumParents() == 0 {
+				break
+			}
+			c = included[c.ParentHashes[0]]
+		}
+	}
+
+	if path == "" {
+		return commits, nil
+	}
+
+	var filtered []*object.Commit
+	for _, c := range commits {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		touches, err := touchesPath(c, path)
+		if err != nil {
+			return nil, err
+		}
+		if touches {
+			filtered = append(filtered, c)
+		}
+	}
+	return filtered, nil
+}
+
+// touchesPath reports whether a commit changes files under path compared to its parents.
+// Like git log -- path, a merge is only included when it differs from every parent.
+func touchesPath(c *object.Commit, path string) (bool, error) {
+	if c.NumParents() == 0 {
+		files, err := treeFiles(c)
+		if err != nil {
+			return false, err
+		}
+		return anyInPath(files, path), nil
+	}
+
+	for i := 0; i < c.NumParents(); i++ {
+		parent, err := c.Parent(i)
+		if err != nil {
+			return false, err
+		}
+		files, err := diffFiles(parent, c)
+		if err != nil {
+			return false, err
+		}
+		if !anyInPath(files, path) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// changedFiles returns the files changed by a commit like git log --name-only,
+// which lists nothing for merge commits
+func changedFiles(c *object.Commit) ([]string, error) {
+	switch c.NumParents() {
+	case 0:
+		return treeFiles(c)
+	case 1:
+		parent, err := c.Parent(0)
+		if err != nil {
+			return nil, err
+		}
+		return diffFiles(parent, c)
+	}
+	return nil, nil
+}
+
+// treeFiles returns every file in the tree of a commit
+func treeFiles(c *object.Commit) ([]string, error) {
+	tree, err := c.Tree()
+	if err != nil {
+		return nil, err
+	}
+
+	var files []string
+	walker := tree.Files()
+	defer walker.Close()
+	for {
+		file, err := walker.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, file.Name)
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// diffFiles returns the files that differ between two commits
+func diffFiles(from, to *object.Commit) ([]string, error) {
+	fromTree, err := from.Tree()
+	if err != nil {
+		return nil, err
+	}
+	toTree, err := to.Tree()
+	if err != nil {
+		return nil, err
+	}
+	changes, err := object.DiffTree(fromTree, toTree)
+	if err != nil {
+		return nil, err
+	}
+
+	var files []string
+	for _, change := range changes {
+		name := change.To.Name
+		if name == "" {
+			name = change.From.Name
+		}
+		files = append(files, name)
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// anyInPath reports whether any of the files is under path
+func anyInPath(files []string, path string) bool {
+	for _, file := range files {
+		if inPath(file, path) {
+			return true
+		}
+	}
+	return false
+}
+
+// inPath reports whether file is path itself or lies under it
+func inPath(file, path string) bool {
+	path = strings.TrimSuffix(filepath.ToSlash(filepath.Clean(path)), "/")
+	return path == "." || file == path || strings.HasPrefix(file, path+"/")
+}
+
+// versionLess orders tag names like git's version sort, comparing runs of digits numerically
+func versionLess(a, b string) bool {
+	for a != "" && b != "" {
+		aDigits, bDigits := leadingDigits(a), leadingDigits(b)
+		if aDigits != "" && bDigits != "" {
+			aNum := strings.TrimLeft(aDigits, "0")
+			bNum := strings.TrimLeft(bDigits, "0")
+			if len(aNum) != len(bNum) {
+				return len(aNum) < len(bNum)
+			}
+			if aNum != bNum {
+				return aNum < bNum
+			}
+			a, b = a[len(aDigits):], b[len(bDigits):]
+			continue
+		}
+		if a[0] != b[0] {
+			return a[0] < b[0]
+		}
+		a, b = a[1:], b[1:]
+	}
+	return len(a) < len(b)
+}
+
+// leadingDigits returns the run of digits at the start of s
+func leadingDigits(s string) string {
+	i := 0
+	for i < len(s) && s[i] >= '0' && s[i] <= '9' {
+		i++
+	}
+	return s[:i]
+}
@@ -0,0 +1,255 @@
+package git
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	gogit "github.com/go-git/go-git/v5"
+)
+
+// runGit runs git commands in dir and fails the test on error
+func runGit(t *testing.T, dir string, cmds ...[]string) {
+	t.Helper()
+	for _, c := range cmds {
+		cmd := exec.Command("git", c...)
+		cmd.Dir = dir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("Failed to run command %v: %v\n%s", c, err, out)
+		}
+	}
+}
+
+// setupHistory extends the test repository with path scoped commits, a merge and a root level change
+func setupHistory(t *testing.T) (string, func()) {
+	t.Helper()
+	dir, cleanup := setupTestRepo(t)
+
+	if err := os.MkdirAll(filepath.Join(dir, "packages", "core"), 0755); err != nil {
+		cleanup()
+		t.Fatalf("Failed to create subdirectory: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "packages", "core", "core.go"), []byte("package core"), 0644); err != nil {
+		cleanup()
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+	runGit(t, dir,
+		[]string{"add", "packages/core/core.go"},
+		[]string{"commit", "-q", "-m", "feat(core): add core", "-m", "Body text."},
+		[]string{"checkout", "-q", "-b", "feature"},
+		[]string{"commit", "-q", "--allow-empty", "-m", "feat: branch work"},
+		[]string{"checkout", "-q", "-"},
+	)
+	if err := os.WriteFile(filepath.Join(dir, "test.txt"), []byte("changed"), 0644); err != nil {
+		cleanup()
+		t.Fatalf("Failed to update test file: %v", err)
+	}
+	runGit(t, dir,
+		[]string{"commit", "-q", "-am", "fix: mainline fix"},
+		[]string{"merge", "-q", "--no-ff", "--no-edit", "feature"},
+	)
+	return dir, cleanup
+}
+
+func TestGoGitMatchesExec(t *testing.T) {
+	dir, cleanup := setupHistory(t)
+	defer cleanup()
+
+	for _, firstParent := range []bool{false, true} {
+		opts := Options{FirstParent: firstParent}
+		exe := NewWithOptions("v*", dir, opts)
+		opts.Backend = "go-git"
+		native := NewWithOptions("v*", dir, opts)
+
+		for _, pattern := range []string{"v*", "core/v*", ""} {
+			want, wantErr := exe.GetLatestTag(pattern)
+			got, err := native.GetLatestTag(pattern)
+			if got != want || (err == nil) != (wantErr == nil) {
+				t.Errorf("GetLatestTag(%q) = %q, %v, want %q, %v", pattern, got, err, want, wantErr)
+			}
+		}
+
+		for _, tc := range []struct{ tag, path string }{
+			{"v2.0.0", ""},
+			{"", ""},
+			{"core/v1.0.0", "packages/core"},
+			{"v1.0.0", "test.txt"},
+		} {
+			// Commit messages are only listed from a tag and always follow every parent
+			if tc.tag != "" && !firstParent {
+				list := func(g Interface) ([]string, error) {
+					if tc.path == "" {
+						return g.GetCommitsSinceTag(tc.tag)
+					}
+					return g.GetCommitsSinceTagForPath(tc.tag, tc.path)
+				}
+				wantCommits, err := list(exe)
+				if err != nil {
+					t.Fatalf("exec GetCommitsSinceTagForPath() error = %v", err)
+				}
+				gotCommits, err := list(native)
+				if err != nil {
+					t.Fatalf("go-git GetCommitsSinceTagForPath() error = %v", err)
+				}
+				if !reflect.DeepEqual(gotCommits, wantCommits) {
+					t.Errorf("GetCommitsSinceTagForPath(%q, %q) = %q, want %q", tc.tag, tc.path, gotCommits, wantCommits)
+				}
+			}
+
+			wantDetails, err := exe.GetCommitDetailsSinceTag(tc.tag, tc.path)
+			if err != nil {
+				t.Fatalf("exec GetCommitDetailsSinceTag() error = %v", err)
+			}
+			gotDetails, err := native.GetCommitDetailsSinceTag(tc.tag, tc.path)
+			if err != nil {
+				t.Fatalf("go-git GetCommitDetailsSinceTag() error = %v", err)
+			}
+			if !reflect.DeepEqual(gotDetails, wantDetails) {
+				t.Errorf("GetCommitDetailsSinceTag(%q, %q) first parent %v = %+v, want %+v", tc.tag, tc.path, firstParent, gotDetails, wantDetails)
+			}
+
+			wantCount, _ := exe.CountCommitsSinceTag(tc.tag, tc.path)
+			gotCount, err := native.CountCommitsSinceTag(tc.tag, tc.path)
+			if err != nil || gotCount != wantCount {
+				t.Errorf("CountCommitsSinceTag(%q, %q) = %v, %v, want %v", tc.tag, tc.path, gotCount, err, wantCount)
+			}
+		}
+	}
+
+	exe := New("v*", dir)
+	native := NewWithOptions("v*", dir, Options{Backend: "go-git"})
+
+	for name, call := range map[string]func(Interface) (string, error){
+		"GetFirstCommit":   Interface.GetFirstCommit,
+		"GetHeadCommit":    Interface.GetHeadCommit,
+		"GetCurrentBranch": Interface.GetCurrentBranch,
+	} {
+		want, _ := call(exe)
+		got, err := call(native)
+		if err != nil || got != want {
+			t.Errorf("%s() = %q, %v, want %q", name, got, err, want)
+		}
+	}
+
+	// A tag on a branch that was never merged is not an ancestor of HEAD
+	runGit(t, dir,
+		[]string{"checkout", "-q", "-b", "unmerged", "HEAD~1"},
+		[]string{"commit", "-q", "--allow-empty", "-m", "feat: unmerged work"},
+		[]string{"tag", "unmerged-tag"},
+		[]string{"checkout", "-q", "-"},
+	)
+	for _, tag := range []string{"v1.0.0", "core/v1.0.0", "feature", "unmerged-tag"} {
+		want, _ := exe.IsAncestor(tag)
+		got, err := native.IsAncestor(tag)
+		if err != nil || got != want {
+			t.Errorf("IsAncestor(%q) = %v, %v, want %v", tag, got, err, want)
+		}
+	}
+}
+
+func TestGitHubAuth(t *testing.T) {
+	dir, cleanup := setupTestRepo(t)
+	defer cleanup()
+
+	os.Setenv("GITHUB_TOKEN", "secret")
+	defer os.Unsetenv("GITHUB_TOKEN")
+	os.Setenv("GITHUB_SERVER_URL", "https://github.example.com")
+	defer os.Unsetenv("GITHUB_SERVER_URL")
+
+	runGit(t, dir, []string{"remote", "add", "origin", "https://github.com/owner/repo.git"})
+	tests := []struct {
+		url  string
+		auth bool
+	}{
+		{"https://github.com/owner/repo.git", true},
+		{"https://GitHub.com/owner/repo", true},
+		{"https://github.example.com/owner/repo.git", true},
+		{"https://gitlab.com/owner/repo.git", false},
+		{"https://github.com.evil.example/owner/repo.git", false},
+		{"git@github.com:owner/repo.git", false},
+		{"ssh://git@github.com/owner/repo.git", false},
+	}
+	for _, tt := range tests {
+		runGit(t, dir, []string{"remote", "set-url", "origin", tt.url})
+		repo, err := gogit.PlainOpen(dir)
+		if err != nil {
+			t.Fatalf("Failed to open repository: %v", err)
+		}
+		auth := githubAuth(repo, "origin")
+		if (auth != nil) != tt.auth {
+			t.Errorf("githubAuth(%q) = %v, want credentials %v", tt.url, auth, tt.auth)
+		}
+		if auth != nil && auth.Password != "secret" {
+			t.Errorf("githubAuth(%q) password = %q, want the token", tt.url, auth.Password)
+		}
+	}
+}
+
+func TestGoGitTagsAndStatus(t *testing.T) {
+	dir, cleanup := setupTestRepo(t)
+	defer cleanup()
+
+	g := NewWithOptions("v*", dir, Options{Backend: "go-git"})
+
+	clean, err := g.IsClean()
+	if err != nil {
+		t.Fatalf("IsClean() error = %v", err)
+	}
+	if !clean {
+		t.Errorf("IsClean() = false, want true")
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "test.txt"), []byte("dirty"), 0644); err != nil {
+		t.Fatalf("Failed to update test file: %v", err)
+	}
+	changes, err := g.HasChanges()
+	if err != nil {
+		t.Fatalf("HasChanges() error = %v", err)
+	}
+	if !changes {
+		t.Errorf("HasChanges() = false, want true")
+	}
+
+	runGit(t, dir, []string{"commit", "-q", "-am", "fix: dirty"})
+	if err := g.CreateTag("v2.0.1", "Release v2.0.1"); err != nil {
+		t.Fatalf("CreateTag() error = %v", err)
+	}
+
+	// The tag must be visible to the git binary as an annotated tag
+	out, err := exec.Command("git", "-C", dir, "cat-file", "-t", "v2.0.1").Output()
+	if err != nil {
+		t.Fatalf("Failed to inspect tag: %v", err)
+	}
+	if string(out) != "tag\n" {
+		t.Errorf("CreateTag() created a %q object, want an annotated tag", out)
+	}
+
+	latest, err := g.GetLatestTag("")
+	if err != nil {
+		t.Fatalf("GetLatestTag() error = %v", err)
+	}
+	if latest != "v2.0.1" {
+		t.Errorf("GetLatestTag() = %v, want v2.0.1", latest)
+	}
+}
+
+func TestVersionLess(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want bool
+	}{
+		{"v1.9.0", "v1.10.0", true},
+		{"v1.10.0", "v1.9.0", false},
+		{"v2.0.0", "v10.0.0", true},
+		{"v1.0.0", "v1.0.0", false},
+		{"core/v1.0.0", "v1.0.0", true},
+	}
+
+	for _, tt := range tests {
+		if got := versionLess(tt.a, tt.b); got != tt.want {
+			t.Errorf("versionLess(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
@@ -1,16 +1,74 @@
 package git
 
-// Interface defines the operations needed by bumpit
+import "context"
+
+// Interface defines the operations needed by bumpit.
+// Every operation has a Context variant that stops the operation when the context is done,
+// the plain variants use context.Background and are only bounded by the configured timeouts.
 type Interface interface {
 	GetLatestTag(pattern string) (string, error)
+	GetTags(pattern string) ([]string, error)
+	GetTagsAtHead(pattern string) ([]string, error)
 	GetCommitsSinceTag(tag string) ([]string, error)
 	GetCommitsSinceTagForPath(tag, path string) ([]string, error)
+	GetCommitDetailsSinceTag(tag, path string) ([]Commit, error)
 	GetFirstCommit() (string, error)
+	GetHeadCommit() (string, error)
+	CountCommitsSinceTag(tag, path string) (int, error)
 	HasChanges() (bool, error)
 	IsClean() (bool, error)
 	CreateTag(tag string, message string) error
+	VerifyTag(tag string) error
+	DeleteTag(tag string) error
 	PushTag(tag string) error
+	PushTags(tags []string) error
+	HasRemoteTag(tag string) (bool, error)
 	GetCurrentBranch() (string, error)
 	GetCurrentVersion() (string, error)
 	GetCommitsSinceVersion(version string) ([]string, error)
+	IsShallow() (bool, error)
+	IsAncestor(tag string) (bool, error)
+	Deepen(depth int) error
+	BehindUpstream() (int, error)
+	Reset(commit string) error
+
+	GetLatestTagContext(ctx context.Context, pattern string) (string, error)
+	GetTagsContext(ctx context.Context, pattern string) ([]string, error)
+	GetTagsAtHeadContext(ctx context.Context, pattern string) ([]string, error)
+	GetCommitsSinceTagContext(ctx context.Context, tag string) ([]string, error)
+	GetCommitsSinceTagForPathContext(ctx context.Context, tag, path string) ([]string, error)
+	GetCommitDetailsSinceTagContext(ctx context.Context, tag, path string) ([]Commit, error)
+	GetFirstCommitContext(ctx context.Context) (string, error)
+	GetHeadCommitContext(ctx context.Context) (string, error)
+	CountCommitsSinceTagContext(ctx context.Context, tag, path string) (int, error)
+	HasChangesContext(ctx context.Context) (bool, error)
+	IsCleanContext(ctx context.Context) (bool, error)
+	CreateTagContext(ctx context.Context, tag string, message string) error
+	VerifyTagContext(ctx context.Context, tag string) error
+	DeleteTagContext(ctx context.Context, tag string) error
+	PushTagContext(ctx context.Context, tag string) error
+	PushTagsContext(ctx context.Context, tags []string) error
+	HasRemoteTagContext(ctx context.Context, tag string) (bool, error)
+	GetCurrentBranchContext(ctx context.Context) (string, error)
+	GetCurrentVersionContext(ctx context.Context) (string, error)
+	GetCommitsSinceVersionContext(ctx context.Context, version string) ([]string, error)
+	IsShallowContext(ctx context.Context) (bool, error)
+	IsAncestorContext(ctx context.Context, tag string) (bool, error)
+	DeepenContext(ctx context.Context, depth int) error
+	BehindUpstreamContext(ctx context.Context) (int, error)
+	ResetContext(ctx context.Context, commit string) error
+}
+
+// Commit holds the details of a single commit
+type Commit struct {
+	Hash    string
+	Parents []string
+	Author  string
+	Message string
+	Files   []string
+}
+
+// IsMerge reports whether the commit has more than one parent
+func (c Commit) IsMerge() bool {
+	return len(c.Parents) > 1
 }
@@ -2,6 +2,9 @@
 package mock
 
 import (
+	"context"
+	"path/filepath"
+
 	"github.com/crazywolf132/bumpit/internal/git"
 )
 
@@ -29,6 +32,30 @@ type Git struct {
 	VersionError               error
 	LatestTagFunc              func(pattern string) (string, error)
 	CommitsSinceTagForPathFunc func(tag string, path string) ([]string, error)
+	CommitDetails              []git.Commit
+	CommitDetailsError         error
+	HeadCommit                 string
+	HeadCommitError            error
+	CommitCount                int
+	CommitCountError           error
+	Shallow                    bool
+	ShallowError               error
+	AncestorFunc               func(tag string) (bool, error)
+	DeepenFunc                 func(depth int) error
+	PushedTags                 []string
+	VerifyTagError             error
+	CreatedTags                []string
+	DeleteTagError             error
+	DeletedTags                []string
+	ResetError                 error
+	ResetCommit                string
+	Tags                       []string
+	TagsError                  error
+	RemoteTags                 []string
+	RemoteTagsError            error
+	HeadTags                   []string
+	Behind                     int
+	BehindError                error
 }
 
 // New creates a new mock Git instance
@@ -54,19 +81,52 @@ func (g *Git) GetCommitsSinceTagForPath(_ string, path string) ([]string, error)
 	return []string{}, nil
 }
 
+// GetCommitDetailsSinceTag returns mocked commit details since a tag.
+func (g *Git) GetCommitDetailsSinceTag(_ string, _ string) ([]git.Commit, error) {
+	return g.CommitDetails, g.CommitDetailsError
+}
+
 // GetFirstCommit returns mock data for the first commit
 func (g *Git) GetFirstCommit() (string, error) {
 	return g.FirstCommit, g.FirstCommitError
 }
 
+// GetHeadCommit returns mock data for the head commit
+func (g *Git) GetHeadCommit() (string, error) {
+	return g.HeadCommit, g.HeadCommitError
+}
+
+// CountCommitsSinceTag returns mock data for the number of commits since a tag
+func (g *Git) CountCommitsSinceTag(_ string, _ string) (int, error) {
+	return g.CommitCount, g.CommitCountError
+}
+
 // HasChanges returns mock data for uncommitted changes
 func (g *Git) HasChanges() (bool, error) {
 	return g.HasChangesResult, g.HasChangesError
 }
 
-// CreateTag creates a mocked tag.
-func (g *Git) CreateTag(_ string, _ string) error {
-	return g.CreateTagError
+// CreateTag records the created tag and returns mock data for tag creation
+func (g *Git) CreateTag(tag string, _ string) error {
+	if g.CreateTagError != nil {
+		return g.CreateTagError
+	}
+	g.CreatedTags = append(g.CreatedTags, tag)
+	return nil
+}
+
+// DeleteTag records the deleted tag and returns mock data for tag deletion
+func (g *Git) DeleteTag(tag string) error {
+	if g.DeleteTagError != nil {
+		return g.DeleteTagError
+	}
+	g.DeletedTags = append(g.DeletedTags, tag)
+	return nil
+}
+
+// VerifyTag returns mock data for tag signature verification
+func (g *Git) VerifyTag(_ string) error {
+	return g.VerifyTagError
 }
 
 // PushTag returns mock data for tag pushing
@@ -74,6 +134,15 @@ func (g *Git) PushTag(_ string) error {
 	return g.PushTagError
 }
 
+// PushTags records the pushed tags and returns mock data for tag pushing
+func (g *Git) PushTags(tags []string) error {
+	if g.PushTagError != nil {
+		return g.PushTagError
+	}
+	g.PushedTags = append(g.PushedTags, tags...)
+	return nil
+}
+
 // GetCurrentBranch returns mock data for current branch
 func (g *Git) GetCurrentBranch() (string, error) {
 	return g.CurrentBranch, g.BranchError
@@ -101,3 +170,280 @@ func (g *Git) GetCurrentVersion() (string, error) {
 func (g *Git) GetCommitsSinceVersion(version string) ([]string, error) {
 	return g.GetCommitsSinceTag(version)
 }
+
+// IsShallow returns mock data for shallow clone detection
+func (g *Git) IsShallow() (bool, error) {
+	return g.Shallow, g.ShallowError
+}
+
+// IsAncestor returns whether the mocked tag is reachable from HEAD, which it is by default
+func (g *Git) IsAncestor(tag string) (bool, error) {
+	if g.AncestorFunc != nil {
+		return g.AncestorFunc(tag)
+	}
+	return true, nil
+}
+
+// Deepen mocks fetching more history
+func (g *Git) Deepen(depth int) error {
+	if g.DeepenFunc != nil {
+		return g.DeepenFunc(depth)
+	}
+	return nil
+}
+
+// Reset records the commit HEAD was reset to and returns mock data for resetting
+func (g *Git) Reset(commit string) error {
+	if g.ResetError != nil {
+		return g.ResetError
+	}
+	g.ResetCommit = commit
+	return nil
+}
+
+// GetTags returns the mocked tags matching the pattern, or all of them when the pattern is empty
+func (g *Git) GetTags(pattern string) ([]string, error) {
+	if g.TagsError != nil {
+		return nil, g.TagsError
+	}
+	return matchTags(pattern, g.Tags), nil
+}
+
+// GetTagsAtHead returns the mocked tags at HEAD matching the pattern, or all of them when the pattern is empty
+func (g *Git) GetTagsAtHead(pattern string) ([]string, error) {
+	if g.TagsError != nil {
+		return nil, g.TagsError
+	}
+	return matchTags(pattern, g.HeadTags), nil
+}
+
+// matchTags returns the tags matching the pattern, or all of them when the pattern is empty
+func matchTags(pattern string, tags []string) []string {
+	if pattern == "" {
+		return tags
+	}
+	var matched []string
+	for _, tag := range tags {
+		if ok, _ := filepath.Match(pattern, tag); ok {
+			matched = append(matched, tag)
+		}
+	}
+	return matched
+}
+
+// HasRemoteTag reports whether the tag is one of the mocked remote tags
+func (g *Git) HasRemoteTag(tag string) (bool, error) {
+	for _, remote := range g.RemoteTags {
+		if remote == tag {
+			return true, nil
+		}
+	}
+	return false, g.RemoteTagsError
+}
+
+// BehindUpstream returns mock data for the number of commits HEAD is behind its upstream
+func (g *Git) BehindUpstream() (int, error) {
+	return g.Behind, g.BehindError
+}
+
+// GetLatestTagContext returns a mocked latest tag, or the context error when the context is done.
+func (g *Git) GetLatestTagContext(ctx context.Context, pattern string) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	return g.GetLatestTag(pattern)
+}
+
+// GetCommitsSinceTagContext returns mocked commits since a tag, or the context error when the context is done.
+func (g *Git) GetCommitsSinceTagContext(ctx context.Context, tag string) ([]string, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return g.GetCommitsSinceTag(tag)
+}
+
+// GetCommitsSinceTagForPathContext returns mocked commits since a tag for a specific path,
+// or the context error when the context is done.
+func (g *Git) GetCommitsSinceTagForPathContext(ctx context.Context, tag string, path string) ([]string, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return g.GetCommitsSinceTagForPath(tag, path)
+}
+
+// GetCommitDetailsSinceTagContext returns mocked commit details since a tag, or the context error when the context is done.
+func (g *Git) GetCommitDetailsSinceTagContext(ctx context.Context, tag string, path string) ([]git.Commit, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return g.GetCommitDetailsSinceTag(tag, path)
+}
+
+// GetFirstCommitContext returns mock data for the first commit, or the context error when the context is done.
+func (g *Git) GetFirstCommitContext(ctx context.Context) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	return g.GetFirstCommit()
+}
+
+// GetHeadCommitContext returns mock data for the head commit, or the context error when the context is done.
+func (g *Git) GetHeadCommitContext(ctx context.Context) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	return g.GetHeadCommit()
+}
+
+// CountCommitsSinceTagContext returns mock data for the number of commits since a tag,
+// or the context error when the context is done.
+func (g *Git) CountCommitsSinceTagContext(ctx context.Context, tag string, path string) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+	return g.CountCommitsSinceTag(tag, path)
+}
+
+// HasChangesContext returns mock data for uncommitted changes, or the context error when the context is done.
+func (g *Git) HasChangesContext(ctx context.Context) (bool, error) {
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+	return g.HasChanges()
+}
+
+// IsCleanContext returns mock data for repository cleanliness, or the context error when the context is done.
+func (g *Git) IsCleanContext(ctx context.Context) (bool, error) {
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+	return g.IsClean()
+}
+
+// CreateTagContext creates a mocked tag, or returns the context error when the context is done.
+func (g *Git) CreateTagContext(ctx context.Context, tag string, message string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return g.CreateTag(tag, message)
+}
+
+// VerifyTagContext returns mock data for tag signature verification, or the context error when the context is done.
+func (g *Git) VerifyTagContext(ctx context.Context, tag string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return g.VerifyTag(tag)
+}
+
+// PushTagContext returns mock data for tag pushing, or the context error when the context is done.
+func (g *Git) PushTagContext(ctx context.Context, tag string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return g.PushTag(tag)
+}
+
+// PushTagsContext records the pushed tags, or returns the context error when the context is done.
+func (g *Git) PushTagsContext(ctx context.Context, tags []string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return g.PushTags(tags)
+}
+
+// GetCurrentBranchContext returns mock data for current branch, or the context error when the context is done.
+func (g *Git) GetCurrentBranchContext(ctx context.Context) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	return g.GetCurrentBranch()
+}
+
+// GetCurrentVersionContext returns mock data for current version, or the context error when the context is done.
+func (g *Git) GetCurrentVersionContext(ctx context.Context) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	return g.GetCurrentVersion()
+}
+
+// GetCommitsSinceVersionContext returns mock data for commits since version, or the context error when the context is done.
+func (g *Git) GetCommitsSinceVersionContext(ctx context.Context, version string) ([]string, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return g.GetCommitsSinceVersion(version)
+}
+
+// IsShallowContext returns mock data for shallow clone detection, or the context error when the context is done.
+func (g *Git) IsShallowContext(ctx context.Context) (bool, error) {
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+	return g.IsShallow()
+}
+
+// IsAncestorContext returns whether the mocked tag is reachable from HEAD, or the context error when the context is done.
+func (g *Git) IsAncestorContext(ctx context.Context, tag string) (bool, error) {
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+	return g.IsAncestor(tag)
+}
+
+// DeepenContext mocks fetching more history, or returns the context error when the context is done.
+func (g *Git) DeepenContext(ctx context.Context, depth int) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return g.Deepen(depth)
+}
+
+// DeleteTagContext records the deleted tag, or returns the context error when the context is done.
+func (g *Git) DeleteTagContext(ctx context.Context, tag string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return g.DeleteTag(tag)
+}
+
+// ResetContext records the commit HEAD was reset to, or returns the context error when the context is done.
+func (g *Git) ResetContext(ctx context.Context, commit string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return g.Reset(commit)
+}
+
+// GetTagsContext returns the mocked tags, or the context error when the context is done.
+func (g *Git) GetTagsContext(ctx context.Context, pattern string) ([]string, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return g.GetTags(pattern)
+}
+
+// HasRemoteTagContext reports whether the tag is one of the mocked remote tags, or returns the context error when the context is done.
+func (g *Git) HasRemoteTagContext(ctx context.Context, tag string) (bool, error) {
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+	return g.HasRemoteTag(tag)
+}
+
+// BehindUpstreamContext returns mock data for the number of commits HEAD is behind its upstream, or the context error when the context is done.
+func (g *Git) BehindUpstreamContext(ctx context.Context) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+	return g.BehindUpstream()
+}
+
+// GetTagsAtHeadContext returns the mocked tags at HEAD, or the context error when the context is done.
+func (g *Git) GetTagsAtHeadContext(ctx context.Context, pattern string) ([]string, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return g.GetTagsAtHead(pattern)
+}
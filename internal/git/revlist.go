@@ -0,0 +1,167 @@
+package git
+
+import (
+	"container/heap"
+	"context"
+
+	gogit "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// revListSlop is how many commits the walk continues after only commits reachable from
+// the excluded side are queued, so commits with skewed dates are still excluded. git uses the same value.
+const revListSlop = 5
+
+// revListNode is a commit seen by revList
+type revListNode struct {
+	commit *object.Commit
+	// uninteresting marks commits reachable from the excluded side
+	uninteresting bool
+	processed     bool
+}
+
+// revListQueue orders the commits to visit, newest commit date first
+type revListQueue []*revListNode
+
+func (q revListQueue) Len() int { return len(q) }
+func (q revListQueue) Less(i, j int) bool {
+	return q[i].commit.Committer.When.After(q[j].commit.Committer.When)
+}
+func (q revListQueue) Swap(i, j int)       { q[i], q[j] = q[j], q[i] }
+func (q *revListQueue) Push(x interface{}) { *q = append(*q, x.(*revListNode)) }
+func (q *revListQueue) Pop() interface{} {
+	old := *q
+	n := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return n
+}
+
+// revList returns the commits reachable from include but not from exclude, newest commit
+// first, like git rev-list include ^exclude. Parents missing from a shallow clone are
+// dropped. Like git, the walk stops shortly after every queued commit is reachable from
+// exclude, so it reads the commits between the two sides instead of the whole history.
+func revList(ctx context.Context, repo *gogit.Repository, include, exclude []plumbing.Hash, missing map[plumbing.Hash]bool) ([]*object.Commit, error) {
+	nodes := make(map[plumbing.Hash]*revListNode)
+	queue := &revListQueue{}
+	interesting := 0 // interesting commits in the queue
+
+	// mark flags a commit and everything already seen below it as reachable from exclude
+	mark := func(n *revListNode) {
+		stack := []*revListNode{n}
+		for len(stack) > 0 {
+			n := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			if n.uninteresting {
+				continue
+			}
+			n.uninteresting = true
+			if !n.processed {
+				interesting--
+				continue
+			}
+			for _, parent := range n.commit.ParentHashes {
+				if p, ok := nodes[parent]; ok {
+					stack = append(stack, p)
+				}
+			}
+		}
+	}
+
+	add := func(hash plumbing.Hash, uninteresting bool) error {
+		if missing[hash] {
+			return nil
+		}
+		if n, ok := nodes[hash]; ok {
+			if uninteresting {
+				mark(n)
+			}
+			return nil
+		}
+		c, err := repo.CommitObject(hash)
+		if err != nil {
+			return err
+		}
+		n := &revListNode{commit: graft(c, missing), uninteresting: uninteresting}
+		nodes[hash] = n
+		heap.Push(queue, n)
+		if !uninteresting {
+			interesting++
+		}
+		return nil
+	}
+
+	for _, hash := range exclude {
+		if err := add(hash, true); err != nil {
+			return nil, err
+		}
+	}
+	for _, hash := range include {
+		if err := add(hash, false); err != nil {
+			return nil, err
+		}
+	}
+
+	var visited []*revListNode
+	slop := revListSlop
+	for queue.Len() > 0 {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		n := heap.Pop(queue).(*revListNode)
+		n.processed = true
+		if !n.uninteresting {
+			interesting--
+			visited = append(visited, n)
+		}
+		for _, parent := range n.commit.ParentHashes {
+			if err := add(parent, n.uninteresting); err != nil {
+				return nil, err
+			}
+		}
+
+		if interesting > 0 {
+			slop = revListSlop
+		} else if slop--; slop == 0 {
+			break
+		}
+	}
+
+	// Commits can be found to be reachable from exclude after they were visited
+	var commits []*object.Commit
+	for _, n := range visited {
+		if !n.uninteresting {
+			commits = append(commits, n.commit)
+		}
+	}
+	return commits, nil
+}
+
+// historyDepth returns the number of generations of history below HEAD that are present,
+// which is the depth of a shallow clone
+func historyDepth(ctx context.Context, repo *gogit.Repository, head plumbing.Hash, missing map[plumbing.Hash]bool) (int, error) {
+	seen := map[plumbing.Hash]bool{head: true}
+	generation := []plumbing.Hash{head}
+	depth := 0
+	for len(generation) > 0 {
+		depth++
+		var next []plumbing.Hash
+		for _, hash := range generation {
+			if err := ctx.Err(); err != nil {
+				return 0, err
+			}
+			c, err := repo.CommitObject(hash)
+			if err != nil {
+				return 0, err
+			}
+			for _, parent := range c.ParentHashes {
+				if !missing[parent] && !seen[parent] {
+					seen[parent] = true
+					next = append(next, parent)
+				}
+			}
+		}
+		generation = next
+	}
+	return depth, nil
+}
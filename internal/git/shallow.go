@@ -0,0 +1,74 @@
+package git
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// Policies for repositories that are shallow clones
+const (
+	// ShallowFail fails when the latest release tag is not reachable in a shallow clone
+	ShallowFail = "fail"
+	// ShallowFetch fetches tags and deepens history until the latest release tag is reachable
+	ShallowFetch = "fetch"
+	// ShallowIgnore uses whatever history the clone has
+	ShallowIgnore = "ignore"
+)
+
+// shallowHint explains how to fix a shallow checkout in CI
+const shallowHint = "the latest release tag is not reachable, so the version would be calculated from incomplete history; " +
+	"fetch the full history (fetch-depth: 0 for actions/checkout) or set git.shallow to \"fetch\""
+
+// EnsureHistory makes sure a shallow clone contains the latest release tag matching pattern
+// and the history since it, so the next version is not calculated from a partial history.
+// With ShallowFail it returns an error wrapping ErrShallowClone, with ShallowFetch it fetches
+// tags and deepens the history by depth commits at a time, or completely when depth is 0.
+// Repositories that are not shallow are left alone.
+func EnsureHistory(ctx context.Context, g Interface, pattern, policy string, depth int) error {
+	if policy == ShallowIgnore {
+		return nil
+	}
+
+	previous := -1
+	for {
+		shallow, err := g.IsShallowContext(ctx)
+		if err != nil || !shallow {
+			return err
+		}
+
+		reachable, err := releaseReachable(ctx, g, pattern)
+		if err != nil || reachable {
+			return err
+		}
+		if policy != ShallowFetch {
+			return fmt.Errorf("%w: %s", ErrShallowClone, shallowHint)
+		}
+
+		// Stop when the remote has no more history to give
+		count, err := g.CountCommitsSinceTagContext(ctx, "", "")
+		if err != nil {
+			return err
+		}
+		if count == previous {
+			return fmt.Errorf("%w: %s", ErrShallowClone, shallowHint)
+		}
+		previous = count
+
+		if err := g.DeepenContext(ctx, depth); err != nil {
+			return err
+		}
+	}
+}
+
+// releaseReachable reports whether the latest release tag exists and is reachable from HEAD
+func releaseReachable(ctx context.Context, g Interface, pattern string) (bool, error) {
+	tag, err := g.GetLatestTagContext(ctx, pattern)
+	if errors.Is(err, ErrNoTags) || errors.Is(err, ErrNoMatchingTags) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return g.IsAncestorContext(ctx, tag)
+}
@@ -0,0 +1,73 @@
+package git
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+// setupShallowClone clones the test repository with only its latest commit,
+// three commits after the latest release tag
+func setupShallowClone(t *testing.T) (string, string, func()) {
+	t.Helper()
+	origin, cleanup := setupTestRepo(t)
+
+	for _, msg := range []string{"fix: one", "fix: two", "feat: three"} {
+		runGit(t, origin, []string{"commit", "-q", "--allow-empty", "-m", msg})
+	}
+
+	clone := t.TempDir()
+	runGit(t, clone, []string{"clone", "-q", "--depth", "1", "file://" + origin, "."})
+	return origin, clone, cleanup
+}
+
+func TestEnsureHistory(t *testing.T) {
+	forEachBackend(t, testEnsureHistory)
+}
+
+func testEnsureHistory(t *testing.T, backend string) {
+	origin, clone, cleanup := setupShallowClone(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	g := NewWithOptions("v*", clone, Options{Backend: backend})
+
+	shallow, err := g.IsShallow()
+	if err != nil {
+		t.Fatalf("IsShallow() error = %v", err)
+	}
+	if !shallow {
+		t.Fatalf("IsShallow() = false, want true for a clone with depth 1")
+	}
+	if _, err := g.GetLatestTag("v*"); !errors.Is(err, ErrNoTags) {
+		t.Errorf("GetLatestTag() in shallow clone error = %v, want ErrNoTags", err)
+	}
+
+	if err := EnsureHistory(ctx, g, "v*", ShallowIgnore, 1); err != nil {
+		t.Errorf("EnsureHistory() with ignore error = %v", err)
+	}
+	if err := EnsureHistory(ctx, g, "v*", ShallowFail, 1); !errors.Is(err, ErrShallowClone) {
+		t.Errorf("EnsureHistory() with fail error = %v, want ErrShallowClone", err)
+	}
+
+	if err := EnsureHistory(ctx, g, "v*", ShallowFetch, 1); err != nil {
+		t.Fatalf("EnsureHistory() with fetch error = %v", err)
+	}
+	tag, err := g.GetLatestTag("v*")
+	if err != nil || tag != "v2.0.0" {
+		t.Fatalf("GetLatestTag() after fetching = %v, %v, want v2.0.0", tag, err)
+	}
+	if reachable, err := g.IsAncestor(tag); err != nil || !reachable {
+		t.Errorf("IsAncestor(%s) = %v, %v, want true", tag, reachable, err)
+	}
+	commits, err := g.GetCommitsSinceTag(tag)
+	if err != nil || len(commits) != 3 {
+		t.Errorf("GetCommitsSinceTag() after fetching = %v, %v, want 3 commits", commits, err)
+	}
+
+	// A complete repository is left alone
+	full := NewWithOptions("v*", origin, Options{Backend: backend})
+	if err := EnsureHistory(ctx, full, "v*", ShallowFail, 1); err != nil {
+		t.Errorf("EnsureHistory() on a complete repository error = %v", err)
+	}
+}
@@ -0,0 +1,220 @@
+package release
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/crazywolf132/bumpit/internal/config"
+)
+
+// Env describes the release a command runs for. Commands receive it as environment variables:
+//
+//	BUMPIT_VERSION           the new version without prefix, e.g. 1.2.0
+//	BUMPIT_PREVIOUS_VERSION  the previous version without prefix, empty for the first release
+//	BUMPIT_TAG               the new release tag, e.g. v1.2.0 or core/v1.2.0
+//	BUMPIT_PREVIOUS_TAG      the previous release tag, empty for the first release
+//	BUMPIT_BUMP              the bump level, e.g. minor
+//	BUMPIT_PATH              the path being released, empty for the whole repository
+//
+// and as the placeholders ${version}, ${previous_version}, ${tag}, ${previous_tag}, ${bump}
+// and ${path} in their arguments.
+type Env struct {
+	Version     string
+	Tag         string
+	PreviousTag string
+	Bump        string
+	Path        string
+}
+
+// previousVersion returns the previous release without the prefix of the new tag,
+// such as 1.1.0 for core/v1.1.0 when the new tag is core/v1.2.0
+func (e Env) previousVersion() string {
+	prefix := strings.TrimSuffix(e.Tag, e.Version)
+	return strings.TrimPrefix(e.PreviousTag, prefix)
+}
+
+// environ returns the environment of a command, the process environment extended with the release
+func (e Env) environ() []string {
+	return append(os.Environ(),
+		"BUMPIT_VERSION="+e.Version,
+		"BUMPIT_PREVIOUS_VERSION="+e.previousVersion(),
+		"BUMPIT_TAG="+e.Tag,
+		"BUMPIT_PREVIOUS_TAG="+e.PreviousTag,
+		"BUMPIT_BUMP="+e.Bump,
+		"BUMPIT_PATH="+e.Path,
+	)
+}
+
+// expand replaces the placeholders in s, passing every value through quote
+func (e Env) expand(s string, quote func(string) string) string {
+	return strings.NewReplacer(
+		"${version}", quote(e.Version),
+		"${previous_version}", quote(e.previousVersion()),
+		"${tag}", quote(e.Tag),
+		"${previous_tag}", quote(e.PreviousTag),
+		"${bump}", quote(e.Bump),
+		"${path}", quote(e.Path),
+	).Replace(s)
+}
+
+// Command returns a step running a command such as default_command. It cannot be undone,
+// but a failing command rolls back the steps completed before it.
+func Command(name string, command config.CommandConfig, env Env) Step {
+	return Step{
+		Name: name,
+		Run: func(ctx context.Context) error {
+			if err := RunCommand(ctx, command, env); err != nil {
+				return fmt.Errorf("command %q failed: %w", describe(command), err)
+			}
+			return nil
+		},
+	}
+}
+
+// RunCommand runs a command for the release, bounded by its timeout. Args and Run are
+// executed directly, with the placeholders replaced in each argument, so values such as
+// the version can never be interpreted as shell syntax. With Shell, Run is passed to sh
+// with the placeholder values quoted for it. On Windows it is passed to cmd, which
+// cannot quote values safely, so such commands read the release from the environment.
+// The output goes to the standard output and error of bumpit.
+func RunCommand(ctx context.Context, command config.CommandConfig, env Env) error {
+	argv, err := commandArgs(command, env)
+	if err != nil {
+		return err
+	}
+
+	if command.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, command.Timeout)
+		defer cancel()
+	}
+
+	cmd := exec.CommandContext(ctx, argv[0], argv[1:]...)
+	cmd.Dir = command.Dir
+	cmd.Env = env.environ()
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	cmd.WaitDelay = time.Second
+
+	err = cmd.Run()
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return fmt.Errorf("timed out after %s: %w", command.Timeout, ctx.Err())
+	}
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	return err
+}
+
+// goos is the platform commands run on, which decides the shell
+var goos = runtime.GOOS
+
+// commandArgs returns the program and arguments that run the command
+func commandArgs(command config.CommandConfig, env Env) ([]string, error) {
+	if command.Shell {
+		if goos == "windows" {
+			// cmd has no quoting that keeps %, ^, & and | in a value literal, so the release only
+			// reaches it through the environment. With delayed expansion, !BUMPIT_VERSION! is
+			// replaced after the command line is parsed, so its value is never run as syntax.
+			if env.expand(command.Run, func(string) string { return "" }) != command.Run {
+				return nil, fmt.Errorf("placeholders cannot be used in shell commands on Windows, use !BUMPIT_VERSION! and the other BUMPIT_* variables instead")
+			}
+			return []string{"cmd", "/V:ON", "/C", command.Run}, nil
+		}
+		return []string{"sh", "-c", env.expand(command.Run, shellQuote)}, nil
+	}
+
+	args := command.Args
+	if len(args) == 0 {
+		var err error
+		if args, err = splitArgs(command.Run); err != nil {
+			return nil, err
+		}
+	}
+	if len(args) == 0 {
+		return nil, fmt.Errorf("no command to run")
+	}
+
+	argv := make([]string, len(args))
+	for i, arg := range args {
+		argv[i] = env.expand(arg, func(s string) string { return s })
+	}
+	return argv, nil
+}
+
+// describe returns the command as it is written in the configuration, for error messages
+func describe(command config.CommandConfig) string {
+	if len(command.Args) > 0 {
+		return strings.Join(command.Args, " ")
+	}
+	return command.Run
+}
+
+// splitArgs splits a command line into arguments like a POSIX shell, honouring single
+// and double quotes and backslash escapes, but without expanding variables or globs
+func splitArgs(line string) ([]string, error) {
+	var args []string
+	var arg strings.Builder
+	inArg := false
+	var quote rune
+
+	runes := []rune(line)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case quote == '\'':
+			if r == '\'' {
+				quote = 0
+			} else {
+				arg.WriteRune(r)
+			}
+		case quote == '"':
+			switch {
+			case r == '"':
+				quote = 0
+			case r == '\\' && i+1 < len(runes) && strings.ContainsRune("\"\\$`", runes[i+1]):
+				i++
+				arg.WriteRune(runes[i])
+			default:
+				arg.WriteRune(r)
+			}
+		case r == '\'' || r == '"':
+			quote = r
+			inArg = true
+		case r == '\\':
+			if i+1 < len(runes) {
+				i++
+				arg.WriteRune(runes[i])
+			}
+			inArg = true
+		case r == ' ' || r == '\t' || r == '\n':
+			if inArg {
+				args = append(args, arg.String())
+				arg.Reset()
+				inArg = false
+			}
+		default:
+			arg.WriteRune(r)
+			inArg = true
+		}
+	}
+
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated %c quote in command %q", quote, line)
+	}
+	if inArg {
+		args = append(args, arg.String())
+	}
+	return args, nil
+}
+
+// shellQuote quotes a value so sh passes it on as a single literal word
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
@@ -0,0 +1,125 @@
+package release
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/crazywolf132/bumpit/internal/config"
+)
+
+func TestSplitArgs(t *testing.T) {
+	tests := []struct {
+		line    string
+		want    []string
+		wantErr bool
+	}{
+		{line: "git tag ${tag}", want: []string{"git", "tag", "${tag}"}},
+		{line: `  npm   version  "${version}" `, want: []string{"npm", "version", "${version}"}},
+		{line: `sed -i 's/version = .*/version = "1"/' setup.py`, want: []string{"sed", "-i", `s/version = .*/version = "1"/`, "setup.py"}},
+		{line: `echo "a \"quoted\" \$HOME" plain\ space ''`, want: []string{"echo", `a "quoted" $HOME`, "plain space", ""}},
+		{line: "", want: nil},
+		{line: `echo "unterminated`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			got, err := splitArgs(tt.line)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("splitArgs() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("splitArgs() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCommandArgs(t *testing.T) {
+	// A version derived from repository data must never reach a shell as syntax
+	env := Env{Version: "1.2.0; touch pwned", Tag: "v1.2.0 $(id)"}
+
+	tests := []struct {
+		name    string
+		command config.CommandConfig
+		want    []string
+	}{
+		{
+			name:    "command line without a shell",
+			command: config.CommandConfig{Run: "git tag -m 'Release ${version}' ${tag}"},
+			want:    []string{"git", "tag", "-m", "Release 1.2.0; touch pwned", "v1.2.0 $(id)"},
+		},
+		{
+			name:    "argv",
+			command: config.CommandConfig{Args: []string{"npm", "version", "${version}"}},
+			want:    []string{"npm", "version", "1.2.0; touch pwned"},
+		},
+		{
+			name:    "shell quotes the values",
+			command: config.CommandConfig{Run: "echo ${version} && echo ${tag}", Shell: true},
+			want:    []string{"sh", "-c", `echo '1.2.0; touch pwned' && echo 'v1.2.0 $(id)'`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := commandArgs(tt.command, env)
+			if err != nil {
+				t.Fatalf("commandArgs() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("commandArgs() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCommandArgsWindowsShell(t *testing.T) {
+	defer func(previous string) { goos = previous }(goos)
+	goos = "windows"
+	env := Env{Version: "1.2.0 & calc %PATH%"}
+
+	got, err := commandArgs(config.CommandConfig{Run: "echo !BUMPIT_VERSION!", Shell: true}, env)
+	if err != nil {
+		t.Fatalf("commandArgs() error = %v", err)
+	}
+	if want := []string{"cmd", "/V:ON", "/C", "echo !BUMPIT_VERSION!"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("commandArgs() = %q, want %q", got, want)
+	}
+
+	if _, err := commandArgs(config.CommandConfig{Run: "echo ${version}", Shell: true}, env); err == nil {
+		t.Error("commandArgs() substituted a placeholder into a cmd command line")
+	}
+	if _, err := commandArgs(config.CommandConfig{Run: "echo ${version}"}, env); err != nil {
+		t.Errorf("commandArgs() without a shell error = %v", err)
+	}
+}
+
+func TestRunCommandShellQuoting(t *testing.T) {
+	dir := t.TempDir()
+	env := Env{Version: "1.2.0'; touch pwned; '"}
+
+	command := config.CommandConfig{Run: "printf %s ${version} > version.txt", Shell: true, Dir: dir}
+	if err := RunCommand(context.Background(), command, env); err != nil {
+		t.Fatalf("RunCommand() error = %v", err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(dir, "version.txt"))
+	if err != nil || string(content) != env.Version {
+		t.Errorf("version.txt = %q, %v, want %q", content, err, env.Version)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "pwned")); !os.IsNotExist(err) {
+		t.Errorf("RunCommand() let the version run a command")
+	}
+}
+
+func TestCommandStep(t *testing.T) {
+	step := Command("default command", config.CommandConfig{Args: []string{"false"}}, Env{})
+	err := step.Run(context.Background())
+	if err == nil || !strings.Contains(err.Error(), `command "false" failed`) {
+		t.Errorf("Command() step error = %v, want the failed command", err)
+	}
+}
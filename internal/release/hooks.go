@@ -0,0 +1,30 @@
+package release
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/crazywolf132/bumpit/internal/config"
+)
+
+// Hooks returns a step running the hooks of a stage such as config.HookPreTag in order.
+// Hooks cannot be undone, but a failing hook rolls back the steps completed before it.
+func Hooks(stage string, hooks []config.CommandConfig, env Env) Step {
+	return Step{
+		Name: stage + " hooks",
+		Run: func(ctx context.Context) error {
+			return RunHooks(ctx, stage, hooks, env)
+		},
+	}
+}
+
+// RunHooks runs the hooks of a stage in order and stops at the first one that fails.
+// Their output goes to the standard output and error of bumpit.
+func RunHooks(ctx context.Context, stage string, hooks []config.CommandConfig, env Env) error {
+	for _, hook := range hooks {
+		if err := RunCommand(ctx, hook, env); err != nil {
+			return fmt.Errorf("%s hook %q failed: %w", stage, describe(hook), err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+package release
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/crazywolf132/bumpit/internal/config"
+	"github.com/crazywolf132/bumpit/internal/git/mock"
+)
+
+func TestRunHooks(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.Mkdir(filepath.Join(dir, "sdk"), 0755); err != nil {
+		t.Fatalf("Failed to create directory: %v", err)
+	}
+	out := filepath.Join(dir, "out.txt")
+
+	env := Env{Version: "1.2.0", Tag: "sdk/v1.2.0", PreviousTag: "sdk/v1.1.0", Bump: "minor", Path: "sdk"}
+	hooks := []config.CommandConfig{
+		{Run: `echo "$BUMPIT_VERSION $BUMPIT_PREVIOUS_VERSION $BUMPIT_TAG $BUMPIT_PREVIOUS_TAG $BUMPIT_BUMP $BUMPIT_PATH" > ` + out, Shell: true},
+		{Run: `basename "$(pwd)" >> ` + out, Shell: true, Dir: filepath.Join(dir, "sdk")},
+	}
+	if err := RunHooks(context.Background(), config.HookPreTag, hooks, env); err != nil {
+		t.Fatalf("RunHooks() error = %v", err)
+	}
+
+	content, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatalf("Failed to read hook output: %v", err)
+	}
+	if want := "1.2.0 1.1.0 sdk/v1.2.0 sdk/v1.1.0 minor sdk\nsdk\n"; string(content) != want {
+		t.Errorf("hook output = %q, want %q", content, want)
+	}
+}
+
+func TestRunHooksFailure(t *testing.T) {
+	out := filepath.Join(t.TempDir(), "out.txt")
+	hooks := []config.CommandConfig{
+		{Run: "exit 3", Shell: true},
+		{Run: "touch " + out},
+	}
+
+	err := RunHooks(context.Background(), config.HookPostTag, hooks, Env{})
+	if err == nil || !strings.Contains(err.Error(), `post_tag hook "exit 3" failed`) {
+		t.Fatalf("RunHooks() error = %v, want the failed hook", err)
+	}
+	if _, err := os.Stat(out); !os.IsNotExist(err) {
+		t.Errorf("RunHooks() ran the hook after the failed one")
+	}
+}
+
+func TestRunHooksTimeout(t *testing.T) {
+	hooks := []config.CommandConfig{{Args: []string{"sleep", "5"}, Timeout: 50 * time.Millisecond}}
+
+	start := time.Now()
+	err := RunHooks(context.Background(), config.HookPreBump, hooks, Env{})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("RunHooks() error = %v, want context.DeadlineExceeded", err)
+	}
+	if time.Since(start) > 3*time.Second {
+		t.Errorf("RunHooks() took %v, want it stopped by the timeout", time.Since(start))
+	}
+}
+
+func TestHooksRollBack(t *testing.T) {
+	g := &mock.Git{}
+	env := Env{Version: "1.2.0", Tag: "v1.2.0"}
+
+	err := Run(context.Background(), []Step{
+		CreateTag(g, "v1.2.0", "Release v1.2.0"),
+		Hooks(config.HookPostTag, []config.CommandConfig{{Run: "false"}}, env),
+	})
+	var releaseErr *Error
+	if !errors.As(err, &releaseErr) || releaseErr.Step != "post_tag hooks" {
+		t.Fatalf("Run() error = %v, want the failed post_tag hooks", err)
+	}
+	if !reflect.DeepEqual(g.DeletedTags, []string{"v1.2.0"}) {
+		t.Errorf("deleted tags = %v, want [v1.2.0]", g.DeletedTags)
+	}
+}
@@ -0,0 +1,122 @@
+package release
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/crazywolf132/bumpit/internal/config"
+	"github.com/crazywolf132/bumpit/internal/git"
+	"github.com/crazywolf132/bumpit/internal/version"
+)
+
+// ErrPreflight is wrapped by every failed pre-flight check
+var ErrPreflight = errors.New("pre-flight check failed")
+
+// Preflight runs the checks enabled in cfg.Preflight before the release of tag changes
+// anything. All checks run, and every failure is reported in the returned error with a
+// message explaining it. Failures wrap ErrPreflight, errors reading the repository do not.
+func Preflight(ctx context.Context, g git.Interface, cfg *config.Config, tag string) error {
+	checks := cfg.Preflight
+	var failures []error
+	fail := func(format string, args ...interface{}) {
+		failures = append(failures, fmt.Errorf("%w: %s", ErrPreflight, fmt.Sprintf(format, args...)))
+	}
+
+	if checks.Clean {
+		clean, err := g.IsCleanContext(ctx)
+		if err != nil {
+			return err
+		}
+		if !clean {
+			fail("the working tree has uncommitted changes, commit or stash them before releasing")
+		}
+	}
+
+	if len(checks.Branches) > 0 {
+		branch, err := g.GetCurrentBranchContext(ctx)
+		if err != nil {
+			return err
+		}
+		if branch == "HEAD" {
+			fail("HEAD is detached, releases are only allowed from %s", strings.Join(checks.Branches, ", "))
+		} else if !branchAllowed(checks.Branches, branch) {
+			fail("branch %s is not allowed to release, releases are only allowed from %s", branch, strings.Join(checks.Branches, ", "))
+		}
+	}
+
+	if checks.UpToDate {
+		behind, err := g.BehindUpstreamContext(ctx)
+		switch {
+		case errors.Is(err, git.ErrNoUpstream):
+			fail("cannot check that HEAD is up to date: %v", err)
+		case err != nil:
+			return err
+		case behind > 0:
+			fail("HEAD is %d commit(s) behind its upstream branch, pull before releasing", behind)
+		}
+	}
+
+	exists := false
+	if checks.UniqueTag {
+		local, err := g.GetTagsContext(ctx, literalPattern(tag))
+		if err != nil {
+			return err
+		}
+		for _, existing := range local {
+			if existing == tag {
+				exists = true
+				fail("tag %s already exists locally", tag)
+				break
+			}
+		}
+	}
+
+	if checks.RemoteTag && !exists {
+		remote, err := g.HasRemoteTagContext(ctx, tag)
+		if err != nil {
+			return err
+		}
+		if remote {
+			fail("tag %s already exists on the remote, fetch tags before releasing", tag)
+		}
+	}
+
+	if checks.NewerVersion {
+		// List every tag with the prefix of the release tag, whatever the configured tag
+		// pattern, which is empty by default and would match no tag
+		tags, err := g.GetTagsContext(ctx, literalPattern(tag[:strings.LastIndex(tag, "/")+1])+"*")
+		if err != nil {
+			return err
+		}
+		for _, existing := range tags {
+			// Tags that are not versions of the same prefix cannot be compared
+			cmp, err := version.CompareTags(cfg, tag, existing)
+			if err == nil && cmp <= 0 {
+				fail("version %s is not newer than the existing tag %s", tag, existing)
+				break
+			}
+		}
+	}
+
+	return errors.Join(failures...)
+}
+
+// literalPattern returns a glob pattern that only matches name, with the wildcards
+// in it wrapped in character classes so they match themselves. Tag names cannot
+// contain a backslash, so no escaping is needed.
+func literalPattern(name string) string {
+	return strings.NewReplacer("*", "[*]", "?", "[?]", "[", "[[]").Replace(name)
+}
+
+// branchAllowed reports whether the branch matches one of the allowed branch patterns
+func branchAllowed(patterns []string, branch string) bool {
+	for _, pattern := range patterns {
+		if matched, _ := path.Match(pattern, branch); matched {
+			return true
+		}
+	}
+	return false
+}
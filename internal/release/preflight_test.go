@@ -0,0 +1,167 @@
+package release
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/crazywolf132/bumpit/internal/config"
+	"github.com/crazywolf132/bumpit/internal/git"
+	"github.com/crazywolf132/bumpit/internal/git/mock"
+)
+
+func TestPreflight(t *testing.T) {
+	allChecks := config.PreflightConfig{
+		Clean:        true,
+		Branches:     []string{"main", "release/*"},
+		UpToDate:     true,
+		UniqueTag:    true,
+		RemoteTag:    true,
+		NewerVersion: true,
+	}
+
+	tests := []struct {
+		name   string
+		checks config.PreflightConfig
+		git    *mock.Git
+		tag    string
+		want   []string
+	}{
+		{
+			name:   "all checks pass",
+			checks: allChecks,
+			git:    &mock.Git{IsCleanResult: true, CurrentBranch: "release/1.x", Tags: []string{"v1.1.0", "v1.0.0", "latest"}},
+			tag:    "v1.2.0",
+		},
+		{
+			name:   "disabled checks are skipped",
+			checks: config.PreflightConfig{},
+			git:    &mock.Git{CurrentBranch: "feature", Behind: 2, Tags: []string{"v1.2.0"}},
+			tag:    "v1.2.0",
+		},
+		{
+			name:   "every failure is reported",
+			checks: allChecks,
+			git:    &mock.Git{CurrentBranch: "feature", Behind: 2, RemoteTags: []string{"v1.2.0"}, Tags: []string{"v1.3.0"}},
+			tag:    "v1.2.0",
+			want: []string{
+				"uncommitted changes",
+				"branch feature is not allowed to release, releases are only allowed from main, release/*",
+				"2 commit(s) behind",
+				"tag v1.2.0 already exists on the remote",
+				"v1.2.0 is not newer than the existing tag v1.3.0",
+			},
+		},
+		{
+			name:   "detached HEAD without upstream",
+			checks: config.PreflightConfig{Branches: []string{"main"}, UpToDate: true},
+			git:    &mock.Git{CurrentBranch: "HEAD", BehindError: git.ErrNoUpstream},
+			tag:    "v1.2.0",
+			want:   []string{"HEAD is detached", "cannot check that HEAD is up to date"},
+		},
+		{
+			name:   "tag exists locally",
+			checks: config.PreflightConfig{UniqueTag: true},
+			git:    &mock.Git{Tags: []string{"v1.2.0"}},
+			tag:    "v1.2.0",
+			want:   []string{"tag v1.2.0 already exists locally"},
+		},
+		{
+			name:   "remote is not contacted by default",
+			checks: config.PreflightConfig{UniqueTag: true},
+			git:    &mock.Git{RemoteTagsError: errors.New("no network")},
+			tag:    "v1.2.0",
+		},
+		{
+			name:   "wildcards in the tag match only the tag",
+			checks: config.PreflightConfig{UniqueTag: true},
+			git:    &mock.Git{Tags: []string{"v1.2.0-a", "v1.2.0-?", "v1.2.0-[ab]"}},
+			tag:    "v1.2.0-*",
+		},
+		{
+			name:   "tag with wildcards exists",
+			checks: config.PreflightConfig{UniqueTag: true},
+			git:    &mock.Git{Tags: []string{"v1.2.0-a", "v1.2.0-*"}},
+			tag:    "v1.2.0-*",
+			want:   []string{"tag v1.2.0-* already exists locally"},
+		},
+		{
+			name:   "pre-release of the same version",
+			checks: config.PreflightConfig{NewerVersion: true},
+			git:    &mock.Git{Tags: []string{"v1.2.0-rc.1"}},
+			tag:    "v1.2.0",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{Preflight: tt.checks}
+			err := Preflight(context.Background(), tt.git, cfg, tt.tag)
+			if len(tt.want) == 0 {
+				if err != nil {
+					t.Fatalf("Preflight() error = %v", err)
+				}
+				return
+			}
+			if !errors.Is(err, ErrPreflight) {
+				t.Fatalf("Preflight() error = %v, want ErrPreflight", err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("Preflight() error = %q, want it to contain %q", err, want)
+				}
+			}
+		})
+	}
+}
+
+func TestPreflightGitError(t *testing.T) {
+	statusErr := errors.New("git status failed")
+	cfg := &config.Config{Preflight: config.PreflightConfig{Clean: true}}
+
+	err := Preflight(context.Background(), &mock.Git{IsCleanError: statusErr}, cfg, "v1.2.0")
+	if !errors.Is(err, statusErr) || errors.Is(err, ErrPreflight) {
+		t.Errorf("Preflight() error = %v, want the git error", err)
+	}
+}
+
+func TestPreflightNewerVersionDefaultConfig(t *testing.T) {
+	dir := t.TempDir()
+	gitOutput(t, dir, "init", "-q")
+	gitOutput(t, dir, "-c", "user.name=Test", "-c", "user.email=test@example.com", "commit", "-q", "--allow-empty", "-m", "feat: first")
+	for _, tag := range []string{"v1.0.0", "v1.3.0", "core/v2.0.0"} {
+		gitOutput(t, dir, "tag", tag)
+	}
+
+	configPath := filepath.Join(dir, ".bumpit.yaml")
+	if err := os.WriteFile(configPath, []byte("version_prefix: \"v\"\n"), 0644); err != nil {
+		t.Fatalf("Failed to write config file: %v", err)
+	}
+	t.Setenv("BUMPIT_CONFIG", configPath)
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	g := git.NewFromConfig(cfg.Git, dir)
+
+	for tag, want := range map[string]string{
+		"v1.2.0":      "v1.2.0 is not newer than the existing tag v1.3.0",
+		"core/v1.5.0": "core/v1.5.0 is not newer than the existing tag core/v2.0.0",
+		"v1.4.0":      "",
+		"core/v2.1.0": "",
+	} {
+		err := Preflight(context.Background(), g, cfg, tag)
+		if want == "" {
+			if err != nil {
+				t.Errorf("Preflight(%s) error = %v", tag, err)
+			}
+			continue
+		}
+		if !errors.Is(err, ErrPreflight) || !strings.Contains(err.Error(), want) {
+			t.Errorf("Preflight(%s) error = %v, want %q", tag, err, want)
+		}
+	}
+}
@@ -0,0 +1,245 @@
+// Package release runs the steps of a release for the bumpit tool.
+// When a step fails, the steps that already completed are undone in reverse order,
+// so a failed release does not leave a local tag, release commit or modified files behind.
+package release
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"github.com/crazywolf132/bumpit/internal/changelog"
+	"github.com/crazywolf132/bumpit/internal/config"
+	"github.com/crazywolf132/bumpit/internal/git"
+)
+
+// Step is a single release step together with the action that compensates it
+type Step struct {
+	// Name describes the step, e.g. "create tag v1.2.0"
+	Name string
+	// Run performs the step
+	Run func(ctx context.Context) error
+	// Undo reverts the completed step and describes what it undid, which is "" when
+	// there was nothing to undo. Steps that cannot be undone leave it nil.
+	Undo func(ctx context.Context) (string, error)
+}
+
+// Error is returned when a release step fails. It wraps the error of the failed step
+// and the errors of compensating actions that failed as well.
+type Error struct {
+	// Step is the name of the failed step
+	Step string
+	// Err is the error of the failed step
+	Err error
+	// Undone describes what the compensating actions undid, in the order they ran
+	Undone []string
+	// UndoErrors are the errors of compensating actions that failed
+	UndoErrors []error
+}
+
+func (e *Error) Error() string {
+	msg := fmt.Sprintf("release step %q failed: %v", e.Step, e.Err)
+	for _, undone := range e.Undone {
+		msg += "\nundone: " + undone
+	}
+	for _, err := range e.UndoErrors {
+		msg += "\nfailed to undo: " + err.Error()
+	}
+	return msg
+}
+
+// Unwrap returns the error of the failed step and the errors of failed compensating actions
+func (e *Error) Unwrap() []error {
+	return append([]error{e.Err}, e.UndoErrors...)
+}
+
+// Run runs the steps in order. When a step fails, the completed steps are undone in
+// reverse order and an *Error is returned. The compensating actions also run when
+// the context is cancelled, so an interrupted release is rolled back as well.
+func Run(ctx context.Context, steps []Step) error {
+	for i, step := range steps {
+		err := ctx.Err()
+		if err == nil {
+			err = step.Run(ctx)
+		}
+		if err != nil {
+			return rollback(context.WithoutCancel(ctx), steps[:i], &Error{Step: step.Name, Err: err})
+		}
+	}
+	return nil
+}
+
+// SignalContext returns a context that is cancelled when the process receives an interrupt
+// or SIGTERM, so that Run stops and rolls back a release interrupted with Ctrl-C or by a CI
+// job being cancelled. Call stop to restore the default handling of the signals.
+func SignalContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	return signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+}
+
+// rollback undoes the completed steps in reverse order and records the outcome in failure
+func rollback(ctx context.Context, completed []Step, failure *Error) error {
+	for i := len(completed) - 1; i >= 0; i-- {
+		step := completed[i]
+		if step.Undo == nil {
+			continue
+		}
+		undone, err := step.Undo(ctx)
+		if undone != "" {
+			failure.Undone = append(failure.Undone, undone)
+		}
+		if err != nil {
+			failure.UndoErrors = append(failure.UndoErrors, fmt.Errorf("%s: %w", step.Name, err))
+		}
+	}
+	return failure
+}
+
+// CreateTag creates the release tag. It is undone by deleting the local tag.
+func CreateTag(g git.Interface, tag, message string) Step {
+	return Step{
+		Name: "create tag " + tag,
+		Run: func(ctx context.Context) error {
+			return g.CreateTagContext(ctx, tag, message)
+		},
+		Undo: func(ctx context.Context) (string, error) {
+			if err := g.DeleteTagContext(ctx, tag); err != nil {
+				return "", err
+			}
+			return "deleted tag " + tag, nil
+		},
+	}
+}
+
+// CreateReleaseTag creates the release tag with the tag message configured in git.tag,
+// rendered with the release notes of the entries. previous is the previous release tag,
+// empty for the first release. A git interface made by git.NewFromConfig creates a
+// lightweight tag instead when git.tag.type is "lightweight", and ignores the message.
+func CreateReleaseTag(g git.Interface, cfg config.TagConfig, tag, version, previous string, entries []changelog.Entry) Step {
+	template := cfg.Message
+	if template == "" {
+		template = "Release {tag}"
+	}
+	return CreateTag(g, tag, changelog.TagMessage(template, tag, version, previous, entries))
+}
+
+// RecordHead remembers the commit HEAD points at. It is undone by resetting HEAD
+// to that commit when a later step made a release commit. The working tree is left
+// as it is, use SnapshotFiles to restore the files the release modified.
+func RecordHead(g git.Interface) Step {
+	var head string
+	return Step{
+		Name: "record HEAD",
+		Run: func(ctx context.Context) error {
+			var err error
+			head, err = g.GetHeadCommitContext(ctx)
+			return err
+		},
+		Undo: func(ctx context.Context) (string, error) {
+			current, err := g.GetHeadCommitContext(ctx)
+			if err != nil {
+				return "", err
+			}
+			if current == head {
+				return "", nil
+			}
+			if err := g.ResetContext(ctx, head); err != nil {
+				return "", err
+			}
+			return "reset release commit " + short(current), nil
+		},
+	}
+}
+
+// short abbreviates a commit hash for reporting
+func short(hash string) string {
+	if len(hash) > 7 {
+		return hash[:7]
+	}
+	return hash
+}
+
+// snapshot is the content of a file before the release, or missing when it did not exist
+type snapshot struct {
+	path    string
+	content []byte
+	mode    fs.FileMode
+	missing bool
+}
+
+// SnapshotFiles records the content of the files a release may modify, such as a
+// changelog or version file. It is undone by restoring the recorded content and
+// removing the files that did not exist before.
+func SnapshotFiles(paths ...string) Step {
+	var snapshots []snapshot
+	return Step{
+		Name: "snapshot " + strings.Join(paths, ", "),
+		Run: func(_ context.Context) error {
+			snapshots = snapshots[:0]
+			for _, path := range paths {
+				info, err := os.Stat(path)
+				if errors.Is(err, fs.ErrNotExist) {
+					snapshots = append(snapshots, snapshot{path: path, missing: true})
+					continue
+				}
+				if err != nil {
+					return fmt.Errorf("failed to snapshot %s: %w", path, err)
+				}
+				content, err := os.ReadFile(path)
+				if err != nil {
+					return fmt.Errorf("failed to snapshot %s: %w", path, err)
+				}
+				snapshots = append(snapshots, snapshot{path: path, content: content, mode: info.Mode().Perm()})
+			}
+			return nil
+		},
+		Undo: func(_ context.Context) (string, error) {
+			var restored []string
+			var errs []error
+			for _, s := range snapshots {
+				changed, err := s.restore()
+				if err != nil {
+					errs = append(errs, err)
+				} else if changed {
+					restored = append(restored, s.path)
+				}
+			}
+			if len(restored) == 0 {
+				return "", errors.Join(errs...)
+			}
+			return "restored " + strings.Join(restored, ", "), errors.Join(errs...)
+		},
+	}
+}
+
+// restore puts the file back the way it was and reports whether it had changed
+func (s snapshot) restore() (bool, error) {
+	current, err := os.ReadFile(s.path)
+	exists := err == nil
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return false, fmt.Errorf("failed to restore %s: %w", s.path, err)
+	}
+
+	if s.missing {
+		if !exists {
+			return false, nil
+		}
+		if err := os.Remove(s.path); err != nil {
+			return false, fmt.Errorf("failed to restore %s: %w", s.path, err)
+		}
+		return true, nil
+	}
+
+	if exists && bytes.Equal(current, s.content) {
+		return false, nil
+	}
+	if err := os.WriteFile(s.path, s.content, s.mode); err != nil {
+		return false, fmt.Errorf("failed to restore %s: %w", s.path, err)
+	}
+	return true, nil
+}
@@ -0,0 +1,201 @@
+package release
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/crazywolf132/bumpit/internal/changelog"
+	"github.com/crazywolf132/bumpit/internal/config"
+	"github.com/crazywolf132/bumpit/internal/git"
+	"github.com/crazywolf132/bumpit/internal/git/mock"
+)
+
+func TestRunRollsBackInReverse(t *testing.T) {
+	dir := t.TempDir()
+	changelog := filepath.Join(dir, "CHANGELOG.md")
+	version := filepath.Join(dir, "VERSION")
+	if err := os.WriteFile(changelog, []byte("## v1.1.0\n"), 0644); err != nil {
+		t.Fatalf("Failed to write changelog: %v", err)
+	}
+
+	g := &mock.Git{HeadCommit: "aaaaaaaaaaaa"}
+	commandErr := errors.New("exit status 1")
+
+	steps := []Step{
+		SnapshotFiles(changelog, version),
+		RecordHead(g),
+		{
+			Name: "commit release",
+			Run: func(context.Context) error {
+				if err := os.WriteFile(changelog, []byte("## v1.2.0\n"), 0644); err != nil {
+					return err
+				}
+				if err := os.WriteFile(version, []byte("1.2.0\n"), 0644); err != nil {
+					return err
+				}
+				g.HeadCommit = "bbbbbbbbbbbb"
+				return nil
+			},
+		},
+		CreateTag(g, "v1.2.0", "Release v1.2.0"),
+		{Name: "run default command", Run: func(context.Context) error { return commandErr }},
+		{Name: "push", Run: func(context.Context) error {
+			t.Error("step after the failed step ran")
+			return nil
+		}},
+	}
+
+	err := Run(context.Background(), steps)
+	var releaseErr *Error
+	if !errors.As(err, &releaseErr) {
+		t.Fatalf("Run() error = %v, want *Error", err)
+	}
+	if !errors.Is(err, commandErr) || releaseErr.Step != "run default command" {
+		t.Errorf("Run() error = %v, want the failed default command", err)
+	}
+
+	want := []string{
+		"deleted tag v1.2.0",
+		"reset release commit bbbbbbb",
+		"restored " + changelog + ", " + version,
+	}
+	if !reflect.DeepEqual(releaseErr.Undone, want) {
+		t.Errorf("Undone = %v, want %v", releaseErr.Undone, want)
+	}
+	if !reflect.DeepEqual(g.DeletedTags, []string{"v1.2.0"}) || g.ResetCommit != "aaaaaaaaaaaa" {
+		t.Errorf("deleted tags = %v, reset to %q", g.DeletedTags, g.ResetCommit)
+	}
+
+	if content, _ := os.ReadFile(changelog); string(content) != "## v1.1.0\n" {
+		t.Errorf("changelog after rollback = %q", content)
+	}
+	if _, err := os.Stat(version); !os.IsNotExist(err) {
+		t.Errorf("version file after rollback exists, want it removed")
+	}
+}
+
+func TestRunReportsFailedUndo(t *testing.T) {
+	deleteErr := errors.New("tag is locked")
+	g := &mock.Git{DeleteTagError: deleteErr}
+
+	err := Run(context.Background(), []Step{
+		CreateTag(g, "v1.2.0", "Release v1.2.0"),
+		{Name: "publish", Run: func(context.Context) error { return errors.New("publish failed") }},
+	})
+	if !errors.Is(err, deleteErr) {
+		t.Fatalf("Run() error = %v, want the undo error", err)
+	}
+	if !strings.Contains(err.Error(), "failed to undo: create tag v1.2.0: tag is locked") {
+		t.Errorf("Run() error = %q, want the failed undo reported", err)
+	}
+}
+
+func TestRunCancelled(t *testing.T) {
+	g := &mock.Git{}
+	ctx, cancel := context.WithCancel(context.Background())
+
+	err := Run(ctx, []Step{
+		CreateTag(g, "v1.2.0", "Release v1.2.0"),
+		{Name: "wait", Run: func(context.Context) error {
+			cancel()
+			return ctx.Err()
+		}},
+		{Name: "push", Run: func(context.Context) error { return nil }},
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("Run() error = %v, want context.Canceled", err)
+	}
+	// The tag is deleted even though the context is done
+	if !reflect.DeepEqual(g.DeletedTags, []string{"v1.2.0"}) {
+		t.Errorf("deleted tags = %v, want [v1.2.0]", g.DeletedTags)
+	}
+}
+
+func TestSignalContext(t *testing.T) {
+	ctx, stop := SignalContext(context.Background())
+	defer stop()
+
+	process, err := os.FindProcess(os.Getpid())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := process.Signal(os.Interrupt); err != nil {
+		t.Skipf("Cannot send an interrupt on this platform: %v", err)
+	}
+	select {
+	case <-ctx.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatal("SignalContext() was not cancelled by an interrupt")
+	}
+
+	err = Run(ctx, []Step{{Name: "tag", Run: func(context.Context) error { return nil }}})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Run() after an interrupt error = %v, want context.Canceled", err)
+	}
+}
+
+func TestRunSucceeds(t *testing.T) {
+	g := &mock.Git{}
+	if err := Run(context.Background(), []Step{CreateTag(g, "v1.2.0", "Release v1.2.0")}); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if len(g.DeletedTags) != 0 || !reflect.DeepEqual(g.CreatedTags, []string{"v1.2.0"}) {
+		t.Errorf("created tags = %v, deleted tags = %v", g.CreatedTags, g.DeletedTags)
+	}
+}
+
+// gitOutput runs git in dir and returns its trimmed output
+func gitOutput(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("Failed to run git %v: %v\n%s", args, err, out)
+	}
+	return strings.TrimSpace(string(out))
+}
+
+func TestCreateReleaseTag(t *testing.T) {
+	dir := t.TempDir()
+	gitOutput(t, dir, "init", "-q")
+	gitOutput(t, dir, "-c", "user.name=Test", "-c", "user.email=test@example.com", "commit", "-q", "--allow-empty", "-m", "feat: first")
+
+	configPath := filepath.Join(dir, ".bumpit.yaml")
+	if err := os.WriteFile(configPath, []byte("git:\n  tag:\n    message: \"{tag} after {previous}\\n\\n{changelog}\"\n"), 0644); err != nil {
+		t.Fatalf("Failed to write config file: %v", err)
+	}
+	t.Setenv("BUMPIT_CONFIG", configPath)
+	t.Setenv("GIT_COMMITTER_NAME", "Test")
+	t.Setenv("GIT_COMMITTER_EMAIL", "test@example.com")
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+
+	entries := changelog.Entries(cfg, []string{"feat: add export", "fix: crash on empty input"})
+	step := CreateReleaseTag(git.NewFromConfig(cfg.Git, dir), cfg.Git.Tag, "v1.1.0", "1.1.0", "v1.0.0", entries)
+	if err := Run(context.Background(), []Step{step}); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	want := "v1.1.0 after v1.0.0\n\n## v1.1.0\n\n### Features\n\n- feat: add export\n\n### Fixes\n\n- fix: crash on empty input"
+	if got := gitOutput(t, dir, "tag", "-l", "--format=%(contents)", "v1.1.0"); got != want {
+		t.Errorf("tag message = %q, want %q", got, want)
+	}
+
+	cfg.Git.Tag.Type = "lightweight"
+	step = CreateReleaseTag(git.NewFromConfig(cfg.Git, dir), cfg.Git.Tag, "v1.2.0", "1.2.0", "v1.1.0", nil)
+	if err := Run(context.Background(), []Step{step}); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if got := gitOutput(t, dir, "cat-file", "-t", "v1.2.0"); got != "commit" {
+		t.Errorf("lightweight tag points at a %s, want the commit", got)
+	}
+}
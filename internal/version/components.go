@@ -0,0 +1,85 @@
+package version
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/crazywolf132/bumpit/internal/config"
+)
+
+// placeholder matches a numeric component such as {major} in a version format
+var placeholder = regexp.MustCompile(`\{([a-z][a-z0-9_]*)\}`)
+
+// componentFormat returns the version format used to parse and render versions with
+// additional components, falling back to dot separated components when the configured
+// format does not name every component.
+func componentFormat(cfg *config.Config) string {
+	levels := cfg.BumpLevels()
+	for _, level := range levels {
+		if !strings.Contains(cfg.VersionFormat, "{"+level+"}") {
+			return "{" + strings.Join(levels, "}.{") + "}"
+		}
+	}
+	return cfg.VersionFormat
+}
+
+// parseComponents extracts the numeric components named in the version format from a version
+func parseComponents(cfg *config.Config, version string) ([]uint64, bool) {
+	format := componentFormat(cfg)
+	levels := cfg.BumpLevels()
+
+	var pattern strings.Builder
+	var order []int
+	last := 0
+	for _, loc := range placeholder.FindAllStringSubmatchIndex(format, -1) {
+		index := -1
+		for i, level := range levels {
+			if level == format[loc[2]:loc[3]] {
+				index = i
+			}
+		}
+		if index == -1 {
+			continue
+		}
+		pattern.WriteString(regexp.QuoteMeta(format[last:loc[0]]))
+		pattern.WriteString(`(\d+)`)
+		order = append(order, index)
+		last = loc[1]
+	}
+	pattern.WriteString(regexp.QuoteMeta(format[last:]))
+
+	re, err := regexp.Compile("^" + pattern.String() + `(?:[-+].*)?$`)
+	if err != nil {
+		return nil, false
+	}
+	m := re.FindStringSubmatch(version)
+	if m == nil {
+		return nil, false
+	}
+
+	components := make([]uint64, len(levels))
+	for i, index := range order {
+		n, err := strconv.ParseUint(m[i+1], 10, 64)
+		if err != nil {
+			return nil, false
+		}
+		components[index] = n
+	}
+	return components, true
+}
+
+// renderVersion formats version components, using the version format when additional components are configured
+func renderVersion(cfg *config.Config, components []uint64) string {
+	if len(cfg.Components) == 0 {
+		return strconv.FormatUint(components[0], 10) + "." +
+			strconv.FormatUint(components[1], 10) + "." +
+			strconv.FormatUint(components[2], 10)
+	}
+
+	rendered := componentFormat(cfg)
+	for i, level := range cfg.BumpLevels() {
+		rendered = strings.ReplaceAll(rendered, "{"+level+"}", strconv.FormatUint(components[i], 10))
+	}
+	return rendered
+}
@@ -0,0 +1,108 @@
+package version
+
+import (
+	"testing"
+
+	"github.com/crazywolf132/bumpit/internal/config"
+)
+
+func newComponentConfig() *config.Config {
+	cfg := newTestConfig()
+	cfg.VersionFormat = "{major}.{minor}.{patch}.{build}"
+	cfg.Components = []config.ComponentConfig{
+		{Name: "build", Triggers: []string{"build:"}},
+	}
+	return cfg
+}
+
+func TestCalculateWithComponents(t *testing.T) {
+	tests := []struct {
+		name    string
+		current string
+		commits []string
+		reset   string
+		want    string
+	}{
+		{
+			name:    "component trigger",
+			current: "v1.2.3.4",
+			commits: []string{"build: rebuild installer"},
+			want:    "v1.2.3.5",
+		},
+		{
+			name:    "patch resets component",
+			current: "v1.2.3.4",
+			commits: []string{"fix: bug fix", "build: rebuild installer"},
+			want:    "v1.2.4.0",
+		},
+		{
+			name:    "component that never resets",
+			current: "v1.2.3.4",
+			commits: []string{"feat: new feature"},
+			reset:   "never",
+			want:    "v1.3.0.4",
+		},
+		{
+			name:    "upgrade from three component tag",
+			current: "core/v1.2.3",
+			commits: []string{"build: rebuild installer"},
+			want:    "core/v1.2.3.1",
+		},
+		{
+			name:    "initial version",
+			commits: []string{"feat: new feature"},
+			want:    "v0.1.0.0",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := newComponentConfig()
+			cfg.Components[0].Reset = tt.reset
+			got, err := New(cfg).Calculate(tt.current, tt.current == "", tt.commits)
+			if err != nil {
+				t.Fatalf("Calculate() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Calculate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestComponentsCustomFormat(t *testing.T) {
+	cfg := newComponentConfig()
+	cfg.VersionFormat = "{major}.{minor}.{patch}+b{build}"
+
+	got, err := New(cfg).Calculate("v2.0.1+b17", false, []string{"build: ci"})
+	if err != nil {
+		t.Fatalf("Calculate() error = %v", err)
+	}
+	if got != "v2.0.1+b18" {
+		t.Errorf("Calculate() = %v, want v2.0.1+b18", got)
+	}
+}
+
+func TestOverrideWithComponents(t *testing.T) {
+	v := New(newComponentConfig())
+
+	got, err := v.CalculateWithOverride("v1.2.3.4", false, nil, Override{Bump: "build"})
+	if err != nil {
+		t.Fatalf("CalculateWithOverride() error = %v", err)
+	}
+	if got.Version != "v1.2.3.5" {
+		t.Errorf("CalculateWithOverride() = %v, want v1.2.3.5", got.Version)
+	}
+
+	got, err = v.CalculateWithOverride("v1.2.3.4", false, nil, Override{Set: "1.2.3.9"})
+	if err != nil {
+		t.Fatalf("CalculateWithOverride() error = %v", err)
+	}
+	if got.Bump != "build" {
+		t.Errorf("CalculateWithOverride() bump = %v, want build", got.Bump)
+	}
+
+	if _, err := v.CalculateWithOverride("v1.2.3.4", false, nil, Override{Set: "1.2.3.4"}); err == nil {
+		t.Errorf("CalculateWithOverride() expected error for a version that is not greater")
+	}
+}
@@ -0,0 +1,98 @@
+package version
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	"github.com/crazywolf132/bumpit/internal/config"
+	"github.com/crazywolf132/bumpit/internal/git"
+)
+
+// shortHashLength is the number of hash characters used in development versions.
+// It is fixed, unlike git's abbreviation, so the same commit always gets the same version.
+const shortHashLength = 7
+
+// Describe returns a development version for HEAD, see Describe
+func (v *Version) Describe(g git.Interface, path string) (string, error) {
+	return Describe(v.cfg, g, path)
+}
+
+// DescribeContext returns a development version for HEAD, see DescribeContext
+func (v *Version) DescribeContext(ctx context.Context, g git.Interface, path string) (string, error) {
+	return DescribeContext(ctx, v.cfg, g, path)
+}
+
+// Describe computes a development version for HEAD between releases, similar to git describe.
+// It combines the next version with the number of commits since the latest release tag and
+// the short hash of HEAD, e.g. "v1.4.0-dev.7+g3f2a1c9", rendered in the configured dialect.
+// When HEAD is the latest release tag, the tag itself is returned.
+func Describe(cfg *config.Config, g git.Interface, path string) (string, error) {
+	return DescribeContext(context.Background(), cfg, g, path)
+}
+
+// DescribeContext computes a development version for HEAD like Describe,
+// stopping the git operations when the context is done.
+func DescribeContext(ctx context.Context, cfg *config.Config, g git.Interface, path string) (string, error) {
+	tag, err := g.GetLatestTagContext(ctx, cfg.Git.TagPattern)
+	if err != nil {
+		if !errors.Is(err, git.ErrNoTags) && !errors.Is(err, git.ErrNoMatchingTags) {
+			return "", err
+		}
+		tag = ""
+	}
+
+	count, err := g.CountCommitsSinceTagContext(ctx, tag, path)
+	if err != nil {
+		return "", err
+	}
+	if count == 0 && tag != "" {
+		return tag, nil
+	}
+
+	commits, err := g.GetCommitDetailsSinceTagContext(ctx, tag, path)
+	if err != nil {
+		return "", err
+	}
+	messages := FilterCommits(cfg, commits)
+
+	var next string
+	if tag == "" {
+		next, err = CalculateInitialVersion(cfg, messages)
+	} else {
+		next, err = CalculateNextVersion(tag, cfg, messages)
+	}
+	// Commits that do not trigger a release still need a version past the tag
+	noRelease := errors.Is(err, ErrNoRelease)
+	if noRelease {
+		next, err = tag, nil
+	}
+	if err != nil {
+		return "", err
+	}
+	prefix, release, err := parseTagVersion(cfg, next)
+	if err != nil {
+		return "", err
+	}
+	if noRelease {
+		release = applyBump(cfg, release, "patch")
+	}
+
+	head, err := g.GetHeadCommitContext(ctx)
+	if err != nil {
+		return "", err
+	}
+	if len(head) > shortHashLength {
+		head = head[:shortHashLength]
+	}
+
+	dialect, err := GetDialect(cfg.Dialect)
+	if err != nil {
+		return "", err
+	}
+	described, err := dialect.Format(renderVersion(cfg, release), PreRelease{Label: "dev", Number: strconv.Itoa(count)}, "g"+head)
+	if err != nil {
+		return "", err
+	}
+	return prefix + "v" + described, nil
+}
@@ -0,0 +1,119 @@
+package version
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/crazywolf132/bumpit/internal/config"
+	"github.com/crazywolf132/bumpit/internal/git"
+	"github.com/crazywolf132/bumpit/internal/git/mock"
+)
+
+func TestDescribe(t *testing.T) {
+	tests := []struct {
+		name       string
+		dialect    string
+		components []config.ComponentConfig
+		git        *mock.Git
+		want       string
+	}{
+		{
+			name: "commits since release",
+			git: &mock.Git{
+				LatestTag:     "v1.3.2",
+				CommitCount:   7,
+				CommitDetails: []git.Commit{{Message: "feat: new feature"}},
+				HeadCommit:    "3f2a1c9d8e7f6a5b4c3d2e1f0a9b8c7d6e5f4a3b",
+			},
+			want: "v1.4.0-dev.7+g3f2a1c9",
+		},
+		{
+			name: "head is released",
+			git: &mock.Git{
+				LatestTag:  "v1.3.2",
+				HeadCommit: "3f2a1c9d8e7f6a5b4c3d2e1f0a9b8c7d6e5f4a3b",
+			},
+			want: "v1.3.2",
+		},
+		{
+			name: "commits without a release still move past the tag",
+			git: &mock.Git{
+				LatestTag:     "core/v1.3.2",
+				CommitCount:   1,
+				CommitDetails: []git.Commit{{Message: "docs: typo\n\n[skip release]"}},
+				HeadCommit:    "abcdef0123456789",
+			},
+			want: "core/v1.3.3-dev.1+gabcdef0",
+		},
+		{
+			name: "no release tag yet",
+			git: &mock.Git{
+				LatestTagError: git.ErrNoTags,
+				CommitCount:    3,
+				CommitDetails:  []git.Commit{{Message: "fix: bug fix"}},
+				HeadCommit:     "1234567890",
+			},
+			want: "v0.0.1-dev.3+g1234567",
+		},
+		{
+			name:    "pep440 dialect",
+			dialect: "pep440",
+			git: &mock.Git{
+				LatestTag:     "v1.3.2",
+				CommitCount:   7,
+				CommitDetails: []git.Commit{{Message: "fix: bug fix"}},
+				HeadCommit:    "3f2a1c9d8e7f",
+			},
+			want: "v1.3.3.dev7+g3f2a1c9",
+		},
+		{
+			name:       "version format containing a v",
+			components: []config.ComponentConfig{{Name: "build", Triggers: []string{"build:"}}},
+			git: &mock.Git{
+				LatestTag:     "v1.3.2.rev4",
+				CommitCount:   2,
+				CommitDetails: []git.Commit{{Message: "build: bump toolchain"}},
+				HeadCommit:    "3f2a1c9d8e7f",
+			},
+			want: "v1.3.2.rev5-dev.2+g3f2a1c9",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := newTestConfig()
+			cfg.Dialect = tt.dialect
+			cfg.Components = tt.components
+			if len(tt.components) > 0 {
+				cfg.VersionFormat = "{major}.{minor}.{patch}.rev{build}"
+			}
+			cfg.Ignore.SkipMarkers = []string{"[skip release]"}
+
+			got, err := New(cfg).Describe(tt.git, "")
+			if err != nil {
+				t.Fatalf("Describe() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Describe() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDescribeGitError(t *testing.T) {
+	g := &mock.Git{LatestTagError: errors.New("failed to get tags: not a git repository")}
+	if _, err := New(newTestConfig()).Describe(g, ""); err == nil {
+		t.Errorf("Describe() expected error when tags cannot be read")
+	}
+}
+
+func TestDescribeContextCancelled(t *testing.T) {
+	g := &mock.Git{LatestTag: "v1.3.2", CommitCount: 1}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := New(newTestConfig()).DescribeContext(ctx, g, ""); !errors.Is(err, context.Canceled) {
+		t.Errorf("DescribeContext() error = %v, want context.Canceled", err)
+	}
+}
@@ -0,0 +1,165 @@
+package version
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// PreRelease is a dialect independent pre-release identifier such as rc.2 or dev.5
+type PreRelease struct {
+	Label  string
+	Number string
+}
+
+// IsZero reports whether the pre-release is empty
+func (p PreRelease) IsZero() bool {
+	return p.Label == "" && p.Number == ""
+}
+
+// preReleaseID matches a pre-release such as "rc.2", "rc2", "beta-1" or "SNAPSHOT"
+var preReleaseID = regexp.MustCompile(`^([A-Za-z]+)[.-]?(\d*)$`)
+
+// ParsePreRelease splits a configured pre-release such as "rc.2" into its label and number.
+// Identifiers that do not follow that shape, such as "alpha.beta.1", are kept whole as the label.
+func ParsePreRelease(s string) PreRelease {
+	m := preReleaseID.FindStringSubmatch(s)
+	if m == nil {
+		return PreRelease{Label: s}
+	}
+	return PreRelease{Label: m[1], Number: m[2]}
+}
+
+// Dialect renders and parses versions in the syntax of a packaging ecosystem
+type Dialect interface {
+	// Format renders a release such as "1.3.0" with a pre-release and build metadata
+	Format(release string, pre PreRelease, metadata string) (string, error)
+	// Parse splits a version into its release and pre-release
+	Parse(version string) (string, PreRelease, error)
+}
+
+// dialects holds the supported dialects by name
+var dialects = map[string]Dialect{
+	"semver": semverDialect{},
+	"pep440": pep440Dialect{},
+	"maven":  mavenDialect{},
+}
+
+// GetDialect returns the dialect with the given name, an empty name selects semver
+func GetDialect(name string) (Dialect, error) {
+	if name == "" {
+		name = "semver"
+	}
+	d, ok := dialects[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown dialect %q", name)
+	}
+	return d, nil
+}
+
+// semverDialect renders versions such as 1.3.0-rc.2+build.5
+type semverDialect struct{}
+
+func (semverDialect) Format(release string, pre PreRelease, metadata string) (string, error) {
+	version := release
+	if !pre.IsZero() {
+		version += "-" + pre.Label
+		if pre.Number != "" {
+			version += "." + pre.Number
+		}
+	}
+	if metadata != "" {
+		version += "+" + metadata
+	}
+	return version, nil
+}
+
+func (semverDialect) Parse(version string) (string, PreRelease, error) {
+	version = strings.SplitN(version, "+", 2)[0]
+	release, id, found := strings.Cut(version, "-")
+	if !found {
+		return release, PreRelease{}, nil
+	}
+	return release, ParsePreRelease(id), nil
+}
+
+// pep440Labels maps pre-release labels to their normalized PEP 440 spelling
+var pep440Labels = map[string]string{
+	"a":       "a",
+	"alpha":   "a",
+	"b":       "b",
+	"beta":    "b",
+	"c":       "rc",
+	"rc":      "rc",
+	"pre":     "rc",
+	"preview": "rc",
+}
+
+// pep440Version matches a PEP 440 version with optional pre, post and dev segments and local version
+var pep440Version = regexp.MustCompile(`^(\d+(?:\.\d+)*)(?:(a|b|rc)(\d+))?(?:\.(post|dev)(\d+))?(?:\+[a-z0-9.]+)?$`)
+
+// pep440Dialect renders versions such as 1.3.0rc2, 1.3.0.dev5 or 1.3.0.post1 for Python packages
+type pep440Dialect struct{}
+
+func (pep440Dialect) Format(release string, pre PreRelease, metadata string) (string, error) {
+	version := release
+	if !pre.IsZero() {
+		number := pre.Number
+		if number == "" {
+			number = "0"
+		}
+		switch label := strings.ToLower(pre.Label); label {
+		case "dev", "snapshot":
+			version += ".dev" + number
+		case "post":
+			version += ".post" + number
+		default:
+			normalized, ok := pep440Labels[label]
+			if !ok {
+				return "", fmt.Errorf("pre-release label %q is not supported by PEP 440", label)
+			}
+			version += normalized + number
+		}
+	}
+	if metadata != "" {
+		version += "+" + strings.ToLower(strings.NewReplacer("-", ".", "_", ".").Replace(metadata))
+	}
+	return version, nil
+}
+
+func (pep440Dialect) Parse(version string) (string, PreRelease, error) {
+	m := pep440Version.FindStringSubmatch(strings.ToLower(version))
+	if m == nil {
+		return "", PreRelease{}, fmt.Errorf("invalid PEP 440 version %q", version)
+	}
+	switch {
+	case m[2] != "":
+		return m[1], PreRelease{Label: m[2], Number: m[3]}, nil
+	case m[4] != "":
+		return m[1], PreRelease{Label: m[4], Number: m[5]}, nil
+	}
+	return m[1], PreRelease{}, nil
+}
+
+// mavenDialect renders versions such as 1.3.0-SNAPSHOT or 1.3.0-rc-2 for Java artifacts
+type mavenDialect struct{}
+
+func (mavenDialect) Format(release string, pre PreRelease, _ string) (string, error) {
+	switch {
+	case pre.IsZero():
+		return release, nil
+	case strings.EqualFold(pre.Label, "snapshot") || strings.EqualFold(pre.Label, "dev"):
+		return release + "-SNAPSHOT", nil
+	case pre.Number == "":
+		return release + "-" + pre.Label, nil
+	}
+	return release + "-" + pre.Label + "-" + pre.Number, nil
+}
+
+func (mavenDialect) Parse(version string) (string, PreRelease, error) {
+	release, qualifier, found := strings.Cut(version, "-")
+	if !found {
+		return release, PreRelease{}, nil
+	}
+	return release, ParsePreRelease(qualifier), nil
+}
@@ -0,0 +1,73 @@
+package version
+
+import (
+	"testing"
+)
+
+func TestRender(t *testing.T) {
+	tests := []struct {
+		name     string
+		dialect  string
+		pre      string
+		metadata string
+		tag      string
+		want     string
+		wantErr  bool
+	}{
+		{name: "semver", dialect: "semver", pre: "rc.2", metadata: "abc123", tag: "v1.3.0", want: "v1.3.0-rc.2+abc123"},
+		{name: "semver complex pre-release", pre: "alpha.beta.1", tag: "v1.3.0", want: "v1.3.0-alpha.beta.1"},
+		{name: "pep440 release candidate", dialect: "pep440", pre: "rc.2", tag: "v1.3.0", want: "v1.3.0rc2"},
+		{name: "pep440 beta", dialect: "pep440", pre: "beta.1", tag: "1.3.0", want: "1.3.0b1"},
+		{name: "pep440 dev", dialect: "pep440", pre: "dev.5", tag: "core/v1.3.0", want: "core/v1.3.0.dev5"},
+		{name: "pep440 local version", dialect: "pep440", metadata: "g3f2a1c9", tag: "1.3.0", want: "1.3.0+g3f2a1c9"},
+		{name: "pep440 unsupported label", dialect: "pep440", pre: "nightly", tag: "1.3.0", wantErr: true},
+		{name: "maven snapshot", dialect: "maven", pre: "SNAPSHOT", tag: "1.3.0", want: "1.3.0-SNAPSHOT"},
+		{name: "maven dev is a snapshot", dialect: "maven", pre: "dev.7", tag: "1.3.0", want: "1.3.0-SNAPSHOT"},
+		{name: "maven release candidate", dialect: "maven", pre: "rc.2", tag: "1.3.0", want: "1.3.0-rc-2"},
+		{name: "unknown dialect", dialect: "npm", tag: "1.3.0", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := newTestConfig()
+			cfg.Dialect = tt.dialect
+			cfg.PreRelease = tt.pre
+			cfg.BuildMetadata = tt.metadata
+
+			got, err := New(cfg).Render(tt.tag)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Render() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Render() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCalculateFromDialectTags(t *testing.T) {
+	tests := []struct {
+		name    string
+		dialect string
+		current string
+		want    string
+	}{
+		{name: "pep440 release candidate", dialect: "pep440", current: "v1.3.0rc2", want: "v1.4.0"},
+		{name: "pep440 dev release", dialect: "pep440", current: "v1.3.0.dev5", want: "v1.4.0"},
+		{name: "maven snapshot", dialect: "maven", current: "v1.3.0-SNAPSHOT", want: "v1.4.0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := newTestConfig()
+			cfg.Dialect = tt.dialect
+			got, err := New(cfg).Calculate(tt.current, false, []string{"feat: new feature"})
+			if err != nil {
+				t.Fatalf("Calculate() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Calculate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
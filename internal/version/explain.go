@@ -0,0 +1,130 @@
+package version
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/crazywolf132/bumpit/internal/config"
+)
+
+// CommitExplanation describes how a single commit was classified
+type CommitExplanation struct {
+	Message  string `json:"message"`
+	Type     string `json:"type"`
+	Rule     string `json:"rule,omitempty"`
+	Decisive bool   `json:"decisive"`
+	// Ignored is set for commits left out by the ignore rules
+	Ignored bool `json:"ignored,omitempty"`
+}
+
+// Explanation describes which commits drove a version bump
+type Explanation struct {
+	CurrentVersion string              `json:"current_version,omitempty"`
+	NextVersion    string              `json:"next_version"`
+	Bump           string              `json:"bump"`
+	Fallback       bool                `json:"fallback"`
+	Commits        []CommitExplanation `json:"commits"`
+}
+
+// Explain calculates the version like Calculate and reports how every commit was classified
+func (v *Version) Explain(currentVersion string, isInitial bool, commits []string) (*Explanation, error) {
+	if isInitial {
+		currentVersion = ""
+	}
+	return Explain(currentVersion, v.cfg, commits)
+}
+
+// Explain classifies each commit using the configured commit types and reports
+// which commit determined the final bump. An empty currentVersion is treated
+// as an initial release.
+func Explain(currentVersion string, cfg *config.Config, commits []string) (*Explanation, error) {
+	var (
+		current []uint64
+		next    string
+		err     error
+	)
+	if currentVersion == "" {
+		next, err = CalculateInitialVersion(cfg, commits)
+	} else {
+		_, current, err = parseTagVersion(cfg, currentVersion)
+		if err == nil {
+			next, err = CalculateNextVersion(currentVersion, cfg, commits)
+		}
+		// Without a release the version stays where it is
+		if errors.Is(err, ErrNoRelease) {
+			next, err = currentVersion, nil
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	bump, decisive, fallback := resolveBump(cfg, current, commits)
+	explanation := &Explanation{
+		CurrentVersion: currentVersion,
+		NextVersion:    next,
+		Bump:           bump,
+		Fallback:       fallback,
+		Commits:        make([]CommitExplanation, 0, len(commits)),
+	}
+
+	for i, commit := range commits {
+		if cfg.IsIgnored(commit, "", nil) {
+			explanation.Commits = append(explanation.Commits, CommitExplanation{Message: commit, Type: "none", Ignored: true})
+			continue
+		}
+		commitType, rule := cfg.MatchCommitType(commit)
+		explanation.Commits = append(explanation.Commits, CommitExplanation{
+			Message:  commit,
+			Type:     commitType,
+			Rule:     rule,
+			Decisive: i == decisive,
+		})
+	}
+
+	return explanation, nil
+}
+
+// String renders the explanation as human readable text
+func (e *Explanation) String() string {
+	var b strings.Builder
+
+	from := e.CurrentVersion
+	if from == "" {
+		from = "(initial)"
+	}
+	fmt.Fprintf(&b, "%s -> %s (%s bump)\n", from, e.NextVersion, e.Bump)
+
+	for _, c := range e.Commits {
+		marker := " "
+		if c.Decisive {
+			marker = "*"
+		}
+		rule := "no matching rule"
+		switch {
+		case c.Ignored:
+			rule = "ignored"
+		case c.Rule != "":
+			rule = fmt.Sprintf("matched %q", c.Rule)
+		}
+		fmt.Fprintf(&b, "%s %-5s  %-24s  %s\n", marker, c.Type, rule, subject(c.Message))
+	}
+
+	switch {
+	case e.Fallback:
+		fmt.Fprintf(&b, "commits matching no commit type defaulted to a %s bump\n", e.Bump)
+	case e.Bump == "none":
+		fmt.Fprintln(&b, "no release")
+	}
+
+	return b.String()
+}
+
+// subject returns the first line of a commit message
+func subject(msg string) string {
+	if i := strings.IndexByte(msg, '\n'); i >= 0 {
+		return msg[:i]
+	}
+	return msg
+}
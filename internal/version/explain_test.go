@@ -0,0 +1,86 @@
+package version
+
+import (
+	"testing"
+)
+
+func TestExplain(t *testing.T) {
+	tests := []struct {
+		name         string
+		current      string
+		commits      []string
+		wantVersion  string
+		wantBump     string
+		wantFallback bool
+		wantDecisive int
+		wantRules    []string
+	}{
+		{
+			name:         "breaking change decides major bump",
+			current:      "v1.2.3",
+			commits:      []string{"fix: bug fix", "feat: new feature", "BREAKING CHANGE: api removed"},
+			wantVersion:  "v2.0.0",
+			wantBump:     "major",
+			wantDecisive: 2,
+			wantRules:    []string{"fix", "feat", "BREAKING CHANGE"},
+		},
+		{
+			name:         "first matching commit decides",
+			current:      "v1.2.3",
+			commits:      []string{"feat: one", "feat: two"},
+			wantVersion:  "v1.3.0",
+			wantBump:     "minor",
+			wantDecisive: 0,
+			wantRules:    []string{"feat", "feat"},
+		},
+		{
+			name:         "unmatched commits fall back to patch",
+			current:      "v1.2.3",
+			commits:      []string{"chore: update deps"},
+			wantVersion:  "v1.2.4",
+			wantBump:     "patch",
+			wantFallback: true,
+			wantDecisive: -1,
+			wantRules:    []string{""},
+		},
+		{
+			name:         "initial release falls back to minor",
+			commits:      []string{},
+			wantVersion:  "v0.1.0",
+			wantBump:     "minor",
+			wantFallback: true,
+			wantDecisive: -1,
+			wantRules:    []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := New(newTestConfig())
+			got, err := v.Explain(tt.current, tt.current == "", tt.commits)
+			if err != nil {
+				t.Fatalf("Explain() error = %v", err)
+			}
+			if got.NextVersion != tt.wantVersion {
+				t.Errorf("Explain() next version = %v, want %v", got.NextVersion, tt.wantVersion)
+			}
+			if got.Bump != tt.wantBump {
+				t.Errorf("Explain() bump = %v, want %v", got.Bump, tt.wantBump)
+			}
+			if got.Fallback != tt.wantFallback {
+				t.Errorf("Explain() fallback = %v, want %v", got.Fallback, tt.wantFallback)
+			}
+			if len(got.Commits) != len(tt.wantRules) {
+				t.Fatalf("Explain() returned %d commits, want %d", len(got.Commits), len(tt.wantRules))
+			}
+			for i, c := range got.Commits {
+				if c.Rule != tt.wantRules[i] {
+					t.Errorf("Explain() commit %d rule = %q, want %q", i, c.Rule, tt.wantRules[i])
+				}
+				if c.Decisive != (i == tt.wantDecisive) {
+					t.Errorf("Explain() commit %d decisive = %v, want %v", i, c.Decisive, i == tt.wantDecisive)
+				}
+			}
+		})
+	}
+}
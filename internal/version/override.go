@@ -0,0 +1,169 @@
+package version
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/crazywolf132/bumpit/internal/config"
+)
+
+// Override forces the outcome of a version calculation regardless of commit history
+type Override struct {
+	// Bump forces a bump of the given level, such as "major", "minor" or "patch"
+	Bump string `json:"bump,omitempty"`
+	// Set forces an exact version such as "2.0.0", written in the configured dialect
+	Set string `json:"set,omitempty"`
+	// Promote releases 1.0.0 from a 0.x version
+	Promote bool `json:"promote,omitempty"`
+}
+
+// IsZero reports whether the override is empty
+func (o Override) IsZero() bool {
+	return o.Bump == "" && o.Set == "" && !o.Promote
+}
+
+// Validate checks that the override is well formed
+func (o Override) Validate() error {
+	if o.Bump != "" && o.Set != "" {
+		return fmt.Errorf("cannot force a bump and set a version at the same time")
+	}
+	if o.Promote && (o.Bump != "" || o.Set != "") {
+		return fmt.Errorf("cannot promote to 1.0.0 while forcing a bump or setting a version")
+	}
+	return nil
+}
+
+// Result is the outcome of a version calculation
+type Result struct {
+	Version         string    `json:"version"`
+	PreviousVersion string    `json:"previous_version,omitempty"`
+	Bump            string    `json:"bump"`
+	IsInitial       bool      `json:"is_initial_version"`
+	Override        *Override `json:"override,omitempty"`
+	// AlreadyReleased means HEAD is already tagged with Version and nothing needs to be released
+	AlreadyReleased bool `json:"already_released,omitempty"`
+}
+
+// CalculateWithOverride calculates the next version like Calculate, unless the
+// override forces a bump or an exact version. A forced version must be greater
+// than the current version.
+func (v *Version) CalculateWithOverride(currentVersion string, isInitial bool, commits []string, override Override) (*Result, error) {
+	if err := override.Validate(); err != nil {
+		return nil, err
+	}
+	if isInitial {
+		currentVersion = ""
+	}
+
+	if override.IsZero() {
+		explanation, err := Explain(currentVersion, v.cfg, commits)
+		if err != nil {
+			return nil, err
+		}
+		return &Result{
+			Version:         explanation.NextVersion,
+			PreviousVersion: currentVersion,
+			Bump:            explanation.Bump,
+			IsInitial:       currentVersion == "",
+		}, nil
+	}
+
+	levels := v.cfg.BumpLevels()
+	if override.Bump != "" && !contains(levels, override.Bump) {
+		return nil, fmt.Errorf("invalid bump %q: must be one of %s", override.Bump, strings.Join(levels, ", "))
+	}
+
+	prefix := ""
+	current := make([]uint64, len(levels))
+	if currentVersion != "" {
+		var err error
+		prefix, current, err = parseTagVersion(v.cfg, currentVersion)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	result := &Result{
+		PreviousVersion: currentVersion,
+		IsInitial:       currentVersion == "",
+		Override:        &override,
+	}
+
+	if override.Promote {
+		if current[0] > 0 {
+			return nil, fmt.Errorf("cannot promote to 1.0.0: current version %s is already stable", renderVersion(v.cfg, current))
+		}
+		stable := make([]uint64, len(levels))
+		stable[0] = 1
+		result.Bump = "major"
+		result.Version = prefix + "v" + renderVersion(v.cfg, stable)
+		return result, nil
+	}
+
+	if override.Bump != "" {
+		result.Bump = override.Bump
+		result.Version = prefix + "v" + renderVersion(v.cfg, applyBump(v.cfg, current, override.Bump))
+		return result, nil
+	}
+
+	forced, err := parseForcedVersion(v.cfg, prefix, override.Set)
+	if err != nil {
+		return nil, fmt.Errorf("invalid version %q: %v", override.Set, err)
+	}
+	if compareComponents(forced, current) <= 0 {
+		return nil, fmt.Errorf("version %s must be greater than current version %s", renderVersion(v.cfg, forced), renderVersion(v.cfg, current))
+	}
+	result.Bump = bumpBetween(levels, current, forced)
+	result.Version = prefix + "v" + renderVersion(v.cfg, forced)
+	return result, nil
+}
+
+// parseForcedVersion parses an explicitly set version into its numeric components, in the
+// configured dialect like tags are. A path prefix must match the prefix of the current tag.
+// The pre-release is configured separately, so a version carrying one is rejected.
+func parseForcedVersion(cfg *config.Config, prefix, version string) ([]uint64, error) {
+	setPrefix, components, err := parseTagVersion(cfg, version)
+	if err != nil {
+		return nil, err
+	}
+	if setPrefix != "" && setPrefix != prefix {
+		return nil, fmt.Errorf("prefix %q does not match the current tag prefix %q", setPrefix, prefix)
+	}
+	if !tagPreRelease(cfg, version).IsZero() {
+		return nil, fmt.Errorf("set the pre-release with pre_release instead")
+	}
+	return components, nil
+}
+
+// compareComponents compares version components from most to least significant
+func compareComponents(a, b []uint64) int {
+	for i := range a {
+		switch {
+		case a[i] > b[i]:
+			return 1
+		case a[i] < b[i]:
+			return -1
+		}
+	}
+	return 0
+}
+
+// bumpBetween returns the most significant component that differs between two versions
+func bumpBetween(levels []string, from, to []uint64) string {
+	for i, level := range levels {
+		if from[i] != to[i] {
+			return level
+		}
+	}
+	return "none"
+}
+
+// contains reports whether list holds s
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,173 @@
+package version
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestCalculateWithOverride(t *testing.T) {
+	tests := []struct {
+		name        string
+		current     string
+		commits     []string
+		override    Override
+		want        string
+		wantBump    string
+		wantErr     bool
+		errContains string
+	}{
+		{
+			name:     "no override uses commits",
+			current:  "v1.2.3",
+			commits:  []string{"feat: new feature"},
+			want:     "v1.3.0",
+			wantBump: "minor",
+		},
+		{
+			name:     "forced bump ignores commits",
+			current:  "v1.2.3",
+			commits:  []string{"fix: bug fix"},
+			override: Override{Bump: "minor"},
+			want:     "v1.3.0",
+			wantBump: "minor",
+		},
+		{
+			name:     "forced bump without commits",
+			current:  "core/v1.2.3",
+			override: Override{Bump: "patch"},
+			want:     "core/v1.2.4",
+			wantBump: "patch",
+		},
+		{
+			name:     "set version keeps tag prefix",
+			current:  "core/v1.2.3",
+			override: Override{Set: "2.0.0"},
+			want:     "core/v2.0.0",
+			wantBump: "major",
+		},
+		{
+			name:     "set initial version",
+			override: Override{Set: "v1.0.0"},
+			want:     "v1.0.0",
+			wantBump: "major",
+		},
+		{
+			name:        "set version must be greater",
+			current:     "v2.0.0",
+			override:    Override{Set: "1.9.0"},
+			wantErr:     true,
+			errContains: "must be greater than current version",
+		},
+		{
+			name:        "set version with a pre-release",
+			current:     "v1.2.3",
+			override:    Override{Set: "2.0.0-rc.1"},
+			wantErr:     true,
+			errContains: "pre_release",
+		},
+		{
+			name:        "set version of another prefix",
+			current:     "core/v1.2.3",
+			override:    Override{Set: "sdk/v2.0.0"},
+			wantErr:     true,
+			errContains: "does not match the current tag prefix",
+		},
+		{
+			name:        "set version equal to current",
+			current:     "v2.0.0",
+			override:    Override{Set: "2.0.0"},
+			wantErr:     true,
+			errContains: "must be greater than current version",
+		},
+		{
+			name:     "promote to 1.0.0",
+			current:  "v0.9.4",
+			commits:  []string{"fix: bug fix"},
+			override: Override{Promote: true},
+			want:     "v1.0.0",
+			wantBump: "major",
+		},
+		{
+			name:        "promote stable version",
+			current:     "v1.2.0",
+			override:    Override{Promote: true},
+			wantErr:     true,
+			errContains: "already stable",
+		},
+		{
+			name:        "invalid bump",
+			current:     "v1.0.0",
+			override:    Override{Bump: "huge"},
+			wantErr:     true,
+			errContains: "invalid bump",
+		},
+		{
+			name:        "bump and set together",
+			current:     "v1.0.0",
+			override:    Override{Bump: "major", Set: "3.0.0"},
+			wantErr:     true,
+			errContains: "at the same time",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := New(newTestConfig())
+			got, err := v.CalculateWithOverride(tt.current, tt.current == "", tt.commits, tt.override)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("CalculateWithOverride() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				if !strings.Contains(err.Error(), tt.errContains) {
+					t.Errorf("CalculateWithOverride() error = %v, want error containing %v", err, tt.errContains)
+				}
+				return
+			}
+			if got.Version != tt.want {
+				t.Errorf("CalculateWithOverride() = %v, want %v", got.Version, tt.want)
+			}
+			if got.Bump != tt.wantBump {
+				t.Errorf("CalculateWithOverride() bump = %v, want %v", got.Bump, tt.wantBump)
+			}
+			if tt.override.IsZero() != (got.Override == nil) {
+				t.Errorf("CalculateWithOverride() override = %v, want %v", got.Override, tt.override)
+			}
+		})
+	}
+}
+
+func TestResultJSONRecordsOverride(t *testing.T) {
+	v := New(newTestConfig())
+	got, err := v.CalculateWithOverride("v1.0.0", false, nil, Override{Bump: "major"})
+	if err != nil {
+		t.Fatalf("CalculateWithOverride() error = %v", err)
+	}
+
+	data, err := json.Marshal(got)
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+	want := `{"version":"v2.0.0","previous_version":"v1.0.0","bump":"major","is_initial_version":false,"override":{"bump":"major"}}`
+	if string(data) != want {
+		t.Errorf("json.Marshal() = %s, want %s", data, want)
+	}
+}
+
+func TestOverrideSetDialect(t *testing.T) {
+	cfg := newTestConfig()
+	cfg.Dialect = "pep440"
+	v := New(cfg)
+
+	got, err := v.CalculateWithOverride("v1.2.3", false, nil, Override{Set: "2.0"})
+	if err != nil {
+		t.Fatalf("CalculateWithOverride() error = %v", err)
+	}
+	if got.Version != "v2.0.0" || got.Bump != "major" {
+		t.Errorf("CalculateWithOverride() = %v (%v), want v2.0.0 (major)", got.Version, got.Bump)
+	}
+
+	if _, err := v.CalculateWithOverride("v1.2.3", false, nil, Override{Set: "2.0.0rc1"}); err == nil {
+		t.Error("CalculateWithOverride() accepted a PEP 440 pre-release")
+	}
+}
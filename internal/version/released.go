@@ -0,0 +1,34 @@
+package version
+
+import (
+	"context"
+	"os"
+
+	"github.com/crazywolf132/bumpit/internal/config"
+	"github.com/crazywolf132/bumpit/internal/git"
+)
+
+// Released returns the result for HEAD when a release tag matching the tag pattern
+// already points at it, so re-running a release on the same commit, e.g. when CI retries
+// a job, reports that version instead of bumping again. It returns nil when HEAD has
+// not been released yet. A release of HEAD is only found for the configured kind of
+// pre-release, so promoting a pre-release tag on the same commit is still possible.
+func Released(ctx context.Context, cfg *config.Config, g git.Interface) (*Result, error) {
+	tags, err := g.GetTagsAtHeadContext(ctx, cfg.Git.TagPattern)
+	if err != nil {
+		return nil, err
+	}
+
+	want := ParsePreRelease(os.ExpandEnv(cfg.PreRelease))
+	for _, tag := range tags {
+		if _, _, err := parseTagVersion(cfg, tag); err != nil {
+			continue
+		}
+		pre := tagPreRelease(cfg, tag)
+		if pre.IsZero() != want.IsZero() || pre.Label != want.Label {
+			continue
+		}
+		return &Result{Version: tag, Bump: "none", AlreadyReleased: true}, nil
+	}
+	return nil, nil
+}
@@ -0,0 +1,70 @@
+package version
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/crazywolf132/bumpit/internal/git/mock"
+)
+
+func TestReleased(t *testing.T) {
+	tests := []struct {
+		name       string
+		headTags   []string
+		preRelease string
+		want       string
+	}{
+		{
+			name:     "HEAD is released",
+			headTags: []string{"v1.3.0", "v1.2.0"},
+			want:     "v1.3.0",
+		},
+		{
+			name: "HEAD is not released",
+		},
+		{
+			name:     "tags that are not versions are ignored",
+			headTags: []string{"vnext"},
+		},
+		{
+			name:     "pre-release can be promoted on the same commit",
+			headTags: []string{"v1.3.0-rc.1"},
+		},
+		{
+			name:       "pre-release is already released",
+			headTags:   []string{"v1.3.0-rc.1"},
+			preRelease: "rc.2",
+			want:       "v1.3.0-rc.1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := newTestConfig()
+			cfg.PreRelease = tt.preRelease
+			got, err := Released(context.Background(), cfg, &mock.Git{HeadTags: tt.headTags})
+			if err != nil {
+				t.Fatalf("Released() error = %v", err)
+			}
+			if tt.want == "" {
+				if got != nil {
+					t.Errorf("Released() = %+v, want nil", got)
+				}
+				return
+			}
+			if got == nil || got.Version != tt.want || !got.AlreadyReleased || got.Bump != "none" {
+				t.Fatalf("Released() = %+v, want %s already released", got, tt.want)
+			}
+
+			out, err := json.Marshal(got)
+			if err != nil {
+				t.Fatalf("json.Marshal() error = %v", err)
+			}
+			if !strings.Contains(string(out), `"already_released":true`) {
+				t.Errorf("Released() JSON = %s, want already_released", out)
+			}
+		})
+	}
+}
@@ -0,0 +1,73 @@
+package version
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/crazywolf132/bumpit/internal/git"
+)
+
+var (
+	// revertBody matches the line git adds to the body of a revert commit
+	revertBody = regexp.MustCompile(`This reverts commit ([0-9a-fA-F]{7,40})`)
+	// revertSubject matches the subject git uses for a revert commit
+	revertSubject = regexp.MustCompile(`^Revert "(.*)"$`)
+)
+
+// dropReverted removes revert commits together with the commits they revert.
+// Reverts of commits outside the given range are kept, since they change
+// previously released behaviour. Reverting a revert restores the original commit.
+func dropReverted(commits []git.Commit) []git.Commit {
+	active := make([]bool, len(commits))
+	cancelled := make(map[int]int) // revert index -> index of the commit it cancelled
+
+	// Walk from the oldest commit so reverts always see their target first
+	for i := len(commits) - 1; i >= 0; i-- {
+		active[i] = true
+
+		target := findRevertTarget(commits, i)
+		if target == -1 {
+			continue
+		}
+
+		active[i] = false
+		active[target] = false
+		if restored, ok := cancelled[target]; ok {
+			active[restored] = true
+		}
+		cancelled[i] = target
+	}
+
+	var kept []git.Commit
+	for i, commit := range commits {
+		if active[i] {
+			kept = append(kept, commit)
+		}
+	}
+	return kept
+}
+
+// findRevertTarget returns the index of the older commit reverted by commits[i],
+// or -1 if it is not a revert or its target is outside the range.
+func findRevertTarget(commits []git.Commit, i int) int {
+	msg := commits[i].Message
+
+	if m := revertBody.FindStringSubmatch(msg); m != nil {
+		hash := strings.ToLower(m[1])
+		for j := i + 1; j < len(commits); j++ {
+			if strings.HasPrefix(strings.ToLower(commits[j].Hash), hash) {
+				return j
+			}
+		}
+		return -1
+	}
+
+	if m := revertSubject.FindStringSubmatch(subject(msg)); m != nil {
+		for j := i + 1; j < len(commits); j++ {
+			if subject(commits[j].Message) == m[1] {
+				return j
+			}
+		}
+	}
+	return -1
+}
@@ -0,0 +1,82 @@
+package version
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/crazywolf132/bumpit/internal/git"
+)
+
+func TestDropReverted(t *testing.T) {
+	tests := []struct {
+		name    string
+		commits []git.Commit
+		want    []string
+	}{
+		{
+			name: "revert by hash cancels both commits",
+			commits: []git.Commit{
+				{Hash: "cccc", Message: "fix: bug fix"},
+				{Hash: "bbbb", Message: "Revert \"feat: new feature\"\n\nThis reverts commit aaaa1111."},
+				{Hash: "aaaa1111", Message: "feat: new feature"},
+			},
+			want: []string{"cccc"},
+		},
+		{
+			name: "revert by subject without hash",
+			commits: []git.Commit{
+				{Hash: "bbbb", Message: "Revert \"feat: new feature\""},
+				{Hash: "aaaa", Message: "feat: new feature"},
+			},
+			want: nil,
+		},
+		{
+			name: "revert of a released commit is kept",
+			commits: []git.Commit{
+				{Hash: "bbbb", Message: "Revert \"feat: old feature\"\n\nThis reverts commit 0123456."},
+			},
+			want: []string{"bbbb"},
+		},
+		{
+			name: "reverting a revert restores the original",
+			commits: []git.Commit{
+				{Hash: "cccc1234", Message: "Revert \"Revert \"feat: new feature\"\"\n\nThis reverts commit bbbb1234."},
+				{Hash: "bbbb1234", Message: "Revert \"feat: new feature\"\n\nThis reverts commit aaaa1234."},
+				{Hash: "aaaa1234", Message: "feat: new feature"},
+			},
+			want: []string{"aaaa1234"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, c := range dropReverted(tt.commits) {
+				got = append(got, c.Hash)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("dropReverted() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFilterCommitsReverts(t *testing.T) {
+	cfg := newTestConfig()
+	cfg.Git.SkipMerges = true
+
+	commits := []git.Commit{
+		{Hash: "dddd", Parents: []string{"cccc", "eeee"}, Message: "Merge branch 'feature'"},
+		{Hash: "cccc", Parents: []string{"bbbb"}, Message: "Revert \"feat: new feature\"\n\nThis reverts commit aaaa."},
+		{Hash: "bbbb", Parents: []string{"aaaa"}, Message: "fix: bug fix"},
+		{Hash: "aaaa", Parents: []string{"ffff"}, Message: "feat: new feature"},
+	}
+
+	got, err := CalculateNextVersion("v1.0.0", cfg, FilterCommits(cfg, commits))
+	if err != nil {
+		t.Fatalf("CalculateNextVersion() error = %v", err)
+	}
+	if got != "v1.0.1" {
+		t.Errorf("CalculateNextVersion() = %v, want v1.0.1", got)
+	}
+}
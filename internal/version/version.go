@@ -3,13 +3,21 @@
 package version
 
 import (
+	"errors"
 	"fmt"
+	"os"
+	"strconv"
 	"strings"
 
 	"github.com/Masterminds/semver/v3"
 	"github.com/crazywolf132/bumpit/internal/config"
+	"github.com/crazywolf132/bumpit/internal/git"
 )
 
+// ErrNoRelease is returned when no commit since the current version requires a release,
+// because every commit was ignored or none of them triggers a bump
+var ErrNoRelease = errors.New("no release needed")
+
 // Version handles version operations
 type Version struct {
 	cfg *config.Config
@@ -20,7 +28,8 @@ func New(cfg *config.Config) *Version {
 	return &Version{cfg: cfg}
 }
 
-// Calculate calculates the version based on current version (if any) and commits
+// Calculate calculates the version based on current version (if any) and commits.
+// It returns an error wrapping ErrNoRelease when the commits do not require a release.
 func (v *Version) Calculate(currentVersion string, isInitial bool, commits []string) (string, error) {
 	if isInitial || currentVersion == "" {
 		version, err := CalculateInitialVersion(v.cfg, commits)
@@ -37,6 +46,31 @@ func (v *Version) Calculate(currentVersion string, isInitial bool, commits []str
 	return version, nil
 }
 
+// Render applies the configured pre-release, build metadata and dialect to a calculated
+// version tag, expanding environment variables in them, e.g. "v1.3.0" becomes "v1.3.0rc2" with the pep440 dialect and pre-release "rc.2"
+func (v *Version) Render(tag string) (string, error) {
+	prefix := ""
+	release := tag
+	if i := strings.LastIndex(tag, "/"); i >= 0 {
+		prefix, release = tag[:i+1], tag[i+1:]
+	}
+	if strings.HasPrefix(release, "v") {
+		prefix += "v"
+		release = release[1:]
+	}
+
+	dialect, err := GetDialect(v.cfg.Dialect)
+	if err != nil {
+		return "", err
+	}
+	pre := ParsePreRelease(os.ExpandEnv(v.cfg.PreRelease))
+	rendered, err := dialect.Format(release, pre, os.ExpandEnv(v.cfg.BuildMetadata))
+	if err != nil {
+		return "", err
+	}
+	return prefix + rendered, nil
+}
+
 // IsValidVersion checks if a version string is valid
 func (v *Version) IsValidVersion(version string) error {
 	// Remove any path prefix
@@ -77,54 +111,113 @@ func (v *Version) CompareVersions(v1, v2 string) (int, error) {
 	return 0, nil
 }
 
+// CompareTags compares the versions of two release tags such as "v1.10.0" and "v1.9.0",
+// returning 1 when a is newer, -1 when b is newer and 0 when they are equal.
+// A release is newer than its pre-releases. Tags with different path prefixes cannot be compared.
+func CompareTags(cfg *config.Config, a, b string) (int, error) {
+	prefixA, componentsA, err := parseTagVersion(cfg, a)
+	if err != nil {
+		return 0, fmt.Errorf("invalid tag %s: %v", a, err)
+	}
+	prefixB, componentsB, err := parseTagVersion(cfg, b)
+	if err != nil {
+		return 0, fmt.Errorf("invalid tag %s: %v", b, err)
+	}
+	if prefixA != prefixB {
+		return 0, fmt.Errorf("cannot compare %s with %s: different prefixes", a, b)
+	}
+	if cmp := compareComponents(componentsA, componentsB); cmp != 0 {
+		return cmp, nil
+	}
+	return comparePreReleases(tagPreRelease(cfg, a), tagPreRelease(cfg, b)), nil
+}
+
+// tagPreRelease returns the pre-release of a tag, which is zero for releases
+func tagPreRelease(cfg *config.Config, tag string) PreRelease {
+	versionNumber := strings.TrimPrefix(tag[strings.LastIndex(tag, "/")+1:], "v")
+	dialect, err := GetDialect(cfg.Dialect)
+	if err != nil {
+		return PreRelease{}
+	}
+	_, pre, err := dialect.Parse(versionNumber)
+	if err != nil {
+		return PreRelease{}
+	}
+	return pre
+}
+
+// comparePreReleases orders pre-releases of the same release by label and then by number,
+// a release without pre-release comes after all of them
+func comparePreReleases(a, b PreRelease) int {
+	switch {
+	case a == b:
+		return 0
+	case a.IsZero():
+		return 1
+	case b.IsZero():
+		return -1
+	case a.Label != b.Label:
+		return strings.Compare(a.Label, b.Label)
+	}
+	numA, _ := strconv.Atoi(a.Number)
+	numB, _ := strconv.Atoi(b.Number)
+	switch {
+	case numA > numB:
+		return 1
+	case numA < numB:
+		return -1
+	}
+	return 0
+}
+
 // CalculateInitialVersion calculates the initial version based on commit messages
 func CalculateInitialVersion(cfg *config.Config, commits []string) (string, error) {
-	major := 0
-	minor := 0
-	patch := 0
-
-	for _, commit := range commits {
-		if containsAny(commit, cfg.CommitTypes.Major) {
-			major = 1
-			minor = 0
-			patch = 0
-			break
-		}
+	bump, _, _ := resolveBump(cfg, nil, commits)
+	initial := make([]uint64, len(cfg.BumpLevels()))
+	return "v" + renderVersion(cfg, applyBump(cfg, initial, bump)), nil
+}
+
+// CalculateNextVersion calculates the next version based on the current version and commit messages.
+// It returns an error wrapping ErrNoRelease instead of the current version when the commits do not
+// require a release, so callers do not tag the current version again.
+func CalculateNextVersion(currentVersion string, cfg *config.Config, commits []string) (string, error) {
+	prefix, current, err := parseTagVersion(cfg, currentVersion)
+	if err != nil {
+		return "", err
 	}
 
-	if major == 0 {
-		for _, commit := range commits {
-			if containsAny(commit, cfg.CommitTypes.Minor) {
-				minor = 1
-				patch = 0
-				break
-			}
-		}
+	bump, _, _ := resolveBump(cfg, current, commits)
+	if bump == "none" {
+		return "", fmt.Errorf("%w: no commit since %s requires a version bump", ErrNoRelease, currentVersion)
 	}
+	return prefix + "v" + renderVersion(cfg, applyBump(cfg, current, bump)), nil
+}
 
-	if major == 0 && minor == 0 {
-		for _, commit := range commits {
-			if containsAny(commit, cfg.CommitTypes.Patch) {
-				patch = 1
-				break
-			}
+// FilterCommits drops reverted work, merge commits when configured and the commits
+// matched by the ignore rules, and returns the messages of the remaining commits
+// for version calculation.
+func FilterCommits(cfg *config.Config, commits []git.Commit) []string {
+	var messages []string
+	for _, commit := range dropReverted(commits) {
+		if cfg.Git.SkipMerges && commit.IsMerge() {
+			continue
 		}
-		// If no commit type matches, default to minor
-		if patch == 0 {
-			minor = 1
+		if cfg.IsIgnored(commit.Message, commit.Author, commit.Files) {
+			continue
 		}
+		messages = append(messages, commit.Message)
 	}
-
-	return fmt.Sprintf("v%d.%d.%d", major, minor, patch), nil
+	return messages
 }
 
-// CalculateNextVersion calculates the next version based on the current version and commit messages
-func CalculateNextVersion(currentVersion string, cfg *config.Config, commits []string) (string, error) {
+// parseTagVersion splits a tag such as "core/v1.2.3" into its path prefix ("core/")
+// and the numeric components of its version, ordered like cfg.BumpLevels().
+func parseTagVersion(cfg *config.Config, tag string) (string, []uint64, error) {
 	// Extract prefix if it exists
 	prefix := ""
-	versionNumber := currentVersion
-	if strings.Contains(currentVersion, "/") {
-		parts := strings.Split(currentVersion, "/")
+	versionNumber := tag
+	if strings.Contains(tag, "/") {
+		parts := strings.Split(tag, "/")
 		prefix = strings.Join(parts[:len(parts)-1], "/") + "/"
 		versionNumber = parts[len(parts)-1]
 	}
@@ -132,89 +225,123 @@ func CalculateNextVersion(currentVersion string, cfg *config.Config, commits []s
 	// Remove 'v' prefix if it exists for semver parsing
 	versionNumber = strings.TrimPrefix(versionNumber, "v")
 
-	v, err := semver.NewVersion(versionNumber)
-	if err != nil {
-		return "", fmt.Errorf("invalid version format: %v", err)
+	// Reduce versions written in another dialect to their release part
+	if cfg.Dialect != "" && cfg.Dialect != "semver" {
+		dialect, err := GetDialect(cfg.Dialect)
+		if err != nil {
+			return "", nil, err
+		}
+		release, _, err := dialect.Parse(versionNumber)
+		if err != nil {
+			return "", nil, fmt.Errorf("invalid version format: %v", err)
+		}
+		versionNumber = release
 	}
 
-	major := v.Major()
-	minor := v.Minor()
-	patch := v.Patch()
-
-	// Check for major version bump
-	for _, commit := range commits {
-		if containsAny(commit, cfg.CommitTypes.Major) {
-			major++
-			minor = 0
-			patch = 0
-			break
+	if len(cfg.Components) > 0 {
+		if components, ok := parseComponents(cfg, versionNumber); ok {
+			return prefix, components, nil
 		}
 	}
 
-	// If no major bump, check for minor
-	if major == v.Major() {
-		for _, commit := range commits {
-			if containsAny(commit, cfg.CommitTypes.Minor) {
-				minor++
-				patch = 0
-				break
-			}
-		}
+	// Plain semantic versions are accepted for every model, additional components start at 0
+	v, err := semver.NewVersion(versionNumber)
+	if err != nil {
+		return "", nil, fmt.Errorf("invalid version format: %v", err)
 	}
+	components := make([]uint64, len(cfg.BumpLevels()))
+	components[0], components[1], components[2] = v.Major(), v.Minor(), v.Patch()
+	return prefix, components, nil
+}
 
-	// If no major or minor bump, check for patch
-	if major == v.Major() && minor == v.Minor() {
-		for _, commit := range commits {
-			if containsAny(commit, cfg.CommitTypes.Patch) {
-				patch++
-				break
-			}
+// applyBump returns the release version obtained by applying bump to the version components.
+// Less significant components are reset to 0 unless their reset rule is "never".
+func applyBump(cfg *config.Config, components []uint64, bump string) []uint64 {
+	next := append([]uint64(nil), components...)
+	for i, level := range cfg.BumpLevels() {
+		if level != bump {
+			continue
 		}
-		// If no commit type matches, default to patch
-		if patch == v.Patch() && len(commits) > 0 {
-			patch++
+		next[i]++
+		for j := i + 1; j < len(next); j++ {
+			if j < 3 || cfg.Components[j-3].Reset != "never" {
+				next[j] = 0
+			}
 		}
 	}
-
-	return fmt.Sprintf("%sv%d.%d.%d", prefix, major, minor, patch), nil
+	return next
 }
 
-// ValidateVersion validates if the version string is a valid semantic version
-func ValidateVersion(version string) error {
-	_, err := semver.NewVersion(version)
-	return err
+// bumpRank orders bump types from least to most significant, "none" being 0.
+func bumpRank(cfg *config.Config, bump string) int {
+	levels := cfg.BumpLevels()
+	for i, level := range levels {
+		if level == bump {
+			return len(levels) - i
+		}
+	}
+	return 0
 }
 
-// CompareVersions compares two version strings and returns:
-//
-//	1 if v1 > v2
-//	-1 if v1 < v2
-//	0 if v1 == v2
-func CompareVersions(v1, v2 string) (int, error) {
-	ver1, err := semver.NewVersion(v1)
-	if err != nil {
-		return 0, fmt.Errorf("invalid version v1: %v", err)
+// determineBump returns the most significant bump type among the commits
+// together with the index of the first commit that triggered it.
+// The index is -1 when no commit triggered a bump. It also reports whether
+// any commit matched none of the configured commit types.
+// Commits whose message matches the ignore rules are skipped, so callers
+// passing plain messages get the same result as with FilterCommits.
+func determineBump(cfg *config.Config, commits []string) (string, int, bool) {
+	bump := "none"
+	decisive := -1
+	unmatched := false
+	for i, commit := range commits {
+		if cfg.IsIgnored(commit, "", nil) {
+			continue
+		}
+		commitType, rule := cfg.MatchCommitType(commit)
+		if rule == "" {
+			unmatched = true
+		}
+		if bumpRank(cfg, commitType) > bumpRank(cfg, bump) {
+			bump = commitType
+			decisive = i
+		}
 	}
+	return bump, decisive, unmatched
+}
 
-	ver2, err := semver.NewVersion(v2)
-	if err != nil {
-		return 0, fmt.Errorf("invalid version v2: %v", err)
-	}
+// resolveBump determines the bump to apply to the current version components, which are nil for an initial release.
+// It returns the bump, the index of the commit that decided it (-1 if none)
+// and whether the bump is a default applied because no commit type matched.
+func resolveBump(cfg *config.Config, current []uint64, commits []string) (string, int, bool) {
+	bump, decisive, unmatched := determineBump(cfg, commits)
 
-	if ver1.GreaterThan(ver2) {
-		return 1, nil
-	} else if ver1.LessThan(ver2) {
-		return -1, nil
+	fallback := false
+	if bump == "none" {
+		switch {
+		case current == nil:
+			// If no commit type matches, default to minor
+			bump = "minor"
+			fallback = true
+		case unmatched && cfg.UnmatchedBump != "none":
+			// If no commit type matches, default to the configured bump
+			bump = "patch"
+			if cfg.UnmatchedBump != "" {
+				bump = cfg.UnmatchedBump
+			}
+			fallback = true
+		}
 	}
-	return 0, nil
-}
 
-// containsAny checks if any of the patterns are contained in the text
-func containsAny(text string, patterns []string) bool {
-	for _, pattern := range patterns {
-		if strings.Contains(text, pattern) {
-			return true
+	// Under the shift policy 0.x releases move breaking changes down to minor
+	// and features down to patch, so only an explicit promotion reaches 1.0.0
+	if cfg.ZeroMajor == "shift" && (current == nil || current[0] == 0) {
+		switch {
+		case bump == "major":
+			bump = "minor"
+		case bump == "minor" && current != nil:
+			bump = "patch"
 		}
 	}
-	return false
+
+	return bump, decisive, fallback
 }
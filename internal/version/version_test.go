@@ -1,9 +1,11 @@
 package version
 
 import (
+	"errors"
 	"testing"
 
 	"github.com/crazywolf132/bumpit/internal/config"
+	"github.com/crazywolf132/bumpit/internal/git"
 )
 
 func newTestConfig() *config.Config {
@@ -64,11 +66,12 @@ func TestCalculateInitialVersion(t *testing.T) {
 
 func TestCalculateNextVersion(t *testing.T) {
 	tests := []struct {
-		name    string
-		current string
-		commits []string
-		want    string
-		wantErr bool
+		name      string
+		current   string
+		commits   []string
+		want      string
+		wantErr   bool
+		noRelease bool
 	}{
 		{
 			name:    "major bump",
@@ -89,10 +92,17 @@ func TestCalculateNextVersion(t *testing.T) {
 			want:    "v1.0.1",
 		},
 		{
-			name:    "no commits",
+			name:    "case-sensitive match",
 			current: "v1.0.0",
-			commits: []string{},
-			want:    "v1.0.0",
+			commits: []string{"Feat: New API", "fix: bug fix"},
+			want:    "v1.0.1",
+		},
+		{
+			name:      "no commits",
+			current:   "v1.0.0",
+			commits:   []string{},
+			wantErr:   true,
+			noRelease: true,
 		},
 	}
 
@@ -100,6 +110,10 @@ func TestCalculateNextVersion(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			v := New(newTestConfig())
 			got, err := v.Calculate(tt.current, false, tt.commits)
+			if tt.noRelease && !errors.Is(err, ErrNoRelease) {
+				t.Errorf("Calculate() error = %v, want ErrNoRelease", err)
+				return
+			}
 			if (err != nil) != tt.wantErr {
 				t.Errorf("Calculate() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -204,3 +218,217 @@ func TestCompareVersions(t *testing.T) {
 		})
 	}
 }
+
+func TestCompareTags(t *testing.T) {
+	tests := []struct {
+		a, b    string
+		want    int
+		wantErr bool
+	}{
+		{a: "v1.10.0", b: "v1.9.0", want: 1},
+		{a: "v1.2.0", b: "v1.2.0", want: 0},
+		{a: "v1.2.0", b: "v1.2.0-rc.1", want: 1},
+		{a: "v1.2.0-rc.2", b: "v1.2.0-rc.10", want: -1},
+		{a: "v1.2.0-beta.1", b: "v1.2.0-rc.1", want: -1},
+		{a: "core/v2.0.0", b: "core/v1.0.0", want: 1},
+		{a: "core/v2.0.0", b: "v1.0.0", wantErr: true},
+		{a: "v1.0.0", b: "latest", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.a+" "+tt.b, func(t *testing.T) {
+			got, err := CompareTags(newTestConfig(), tt.a, tt.b)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("CompareTags() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && got != tt.want {
+				t.Errorf("CompareTags() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestZeroMajorShift(t *testing.T) {
+	tests := []struct {
+		name    string
+		current string
+		commits []string
+		want    string
+	}{
+		{
+			name:    "breaking change bumps minor",
+			current: "v0.3.1",
+			commits: []string{"BREAKING CHANGE: something"},
+			want:    "v0.4.0",
+		},
+		{
+			name:    "feature bumps patch",
+			current: "v0.3.1",
+			commits: []string{"feat: new feature"},
+			want:    "v0.3.2",
+		},
+		{
+			name:    "stable versions follow semver",
+			current: "v1.3.1",
+			commits: []string{"BREAKING CHANGE: something"},
+			want:    "v2.0.0",
+		},
+		{
+			name:    "initial breaking change stays below 1.0.0",
+			commits: []string{"BREAKING CHANGE: something"},
+			want:    "v0.1.0",
+		},
+		{
+			name:    "initial feature",
+			commits: []string{"feat: new feature"},
+			want:    "v0.1.0",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := newTestConfig()
+			cfg.ZeroMajor = "shift"
+			v := New(cfg)
+			got, err := v.Calculate(tt.current, tt.current == "", tt.commits)
+			if err != nil {
+				t.Fatalf("Calculate() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Calculate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUnmatchedBump(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy string
+		want   string
+	}{
+		{name: "default patch", policy: "", want: "v1.0.1"},
+		{name: "minor", policy: "minor", want: "v1.1.0"},
+		{name: "no release", policy: "none"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := newTestConfig()
+			cfg.UnmatchedBump = tt.policy
+			got, err := New(cfg).Calculate("v1.0.0", false, []string{"chore: update deps"})
+			if tt.want == "" {
+				if !errors.Is(err, ErrNoRelease) {
+					t.Errorf("Calculate() = %v, %v, want ErrNoRelease", got, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Calculate() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Calculate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFilterCommits(t *testing.T) {
+	cfg := newTestConfig()
+	cfg.Ignore = config.IgnoreConfig{
+		Types:       []string{"chore(release)"},
+		Authors:     []string{"dependabot"},
+		SkipMarkers: []string{"[skip release]"},
+	}
+
+	commits := []git.Commit{
+		{Message: "chore(release): v1.1.0"},
+		{Message: "fix: bump dependency", Author: "dependabot[bot] <support@github.com>"},
+		{Message: "feat: experimental\n\n[skip release]"},
+		{Message: "fix: real bug", Author: "Jane <jane@example.com>"},
+	}
+
+	got := FilterCommits(cfg, commits)
+	if len(got) != 1 || got[0] != "fix: real bug" {
+		t.Errorf("FilterCommits() = %v, want [fix: real bug]", got)
+	}
+
+	next, err := CalculateNextVersion("v1.1.0", cfg, FilterCommits(cfg, commits[:3]))
+	if !errors.Is(err, ErrNoRelease) {
+		t.Errorf("CalculateNextVersion() = %v, %v, want ErrNoRelease when every commit is ignored", next, err)
+	}
+
+	// Plain messages skip the rules that only need the message
+	messages := []string{"chore(release): v1.1.0", "feat: experimental\n\n[skip release]", "fix: real bug"}
+	next, err = CalculateNextVersion("v1.1.0", cfg, messages)
+	if err != nil {
+		t.Fatalf("CalculateNextVersion() error = %v", err)
+	}
+	if next != "v1.1.1" {
+		t.Errorf("CalculateNextVersion() = %v, want v1.1.1 with the ignored messages skipped", next)
+	}
+	explanation, err := Explain("v1.1.0", cfg, messages)
+	if err != nil {
+		t.Fatalf("Explain() error = %v", err)
+	}
+	if !explanation.Commits[1].Ignored || explanation.Commits[1].Decisive || !explanation.Commits[2].Decisive {
+		t.Errorf("Explain() commits = %+v, want the skip marker ignored and the fix decisive", explanation.Commits)
+	}
+}
+
+func TestSquashSubjectOnly(t *testing.T) {
+	squashed := []string{"chore: update tooling (#12)\n\n* fix lint\n* fix flaky test"}
+
+	cfg := newTestConfig()
+	cfg.UnmatchedBump = "none"
+	got, err := New(cfg).Calculate("v1.0.0", false, squashed)
+	if err != nil {
+		t.Fatalf("Calculate() error = %v", err)
+	}
+	if got != "v1.0.1" {
+		t.Errorf("Calculate() = %v, want v1.0.1 when the body is analysed", got)
+	}
+
+	cfg.Squash.SubjectOnly = true
+	got, err = New(cfg).Calculate("v1.0.0", false, squashed)
+	if !errors.Is(err, ErrNoRelease) {
+		t.Errorf("Calculate() = %v, %v, want ErrNoRelease when only the subject is analysed", got, err)
+	}
+}
+
+func TestCommitRules(t *testing.T) {
+	cfg := newTestConfig()
+	cfg.CommitTypes.Rules = []config.CommitRule{
+		{Type: "feat", Scope: "docs", Bump: "patch"},
+		{Type: "docs", Bump: "none"},
+	}
+
+	tests := []struct {
+		name    string
+		commits []string
+		want    string
+	}{
+		{name: "feat in api is minor", commits: []string{"feat(api): endpoint"}, want: "v1.1.0"},
+		{name: "feat in docs is patch", commits: []string{"feat(docs): guide"}, want: "v1.0.1"},
+		{name: "explicit none does not fall back", commits: []string{"docs: typo"}},
+		{name: "unmatched commit still falls back", commits: []string{"docs: typo", "chore: deps"}, want: "v1.0.1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := New(cfg).Calculate("v1.0.0", false, tt.commits)
+			if tt.want == "" {
+				if !errors.Is(err, ErrNoRelease) {
+					t.Errorf("Calculate() = %v, %v, want ErrNoRelease", got, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Calculate() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Calculate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}